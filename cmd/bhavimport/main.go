@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"pkg/bhavcopy"
+	"pkg/cfg"
+	"pkg/store"
+	"sort"
+	"strings"
+)
+
+const usage = `Usage:
+  bhavimport <bhavcopy folder> <output folder> [series,...]   merge into SYMBOL.csv files
+  bhavimport <bhavcopy folder> store [series,...]             upsert into the quote store
+Series default to EQ,BE. Zipped bhavcopies and delivery position files are read too.`
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	series := bhavcopy.DefaultSeries
+	if len(os.Args) > 3 {
+		series = strings.Split(strings.ToUpper(os.Args[3]), ",")
+	}
+	records, err := bhavcopy.ReadDir(os.Args[1], series)
+	if err != nil {
+		log.Fatal(err)
+	}
+	histories := bhavcopy.Histories(records)
+	symbols := make([]string, 0, len(histories))
+	for symbol := range histories {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	log.Printf("%d records of series %s, %d symbols", len(records), strings.Join(series, ","), len(symbols))
+
+	if os.Args[2] == "store" {
+		toStore(histories, symbols)
+		return
+	}
+	if err := os.MkdirAll(os.Args[2], 0755); err != nil {
+		log.Fatal(err)
+	}
+	for _, symbol := range symbols {
+		report, err := histories[symbol].WriteFile(filepath.Join(os.Args[2], symbol+".csv"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(report)
+	}
+}
+
+func toStore(histories map[string]*bhavcopy.History, symbols []string) {
+	db, err := store.Open(cfg.QuoteStore())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	for _, symbol := range symbols {
+		h := histories[symbol]
+		n, err := db.Upsert(h.QuoteData())
+		if err != nil {
+			log.Fatalf("%s: %v", symbol, err)
+		}
+		activity := make([]store.Activity, len(h.Sessions))
+		for i, rec := range h.Sessions {
+			activity[i] = store.Activity{Date: rec.Date, Turnover: rec.Turnover, Delivery: rec.Delivery, Trades: rec.Trades}
+		}
+		if err := db.UpsertActivity(symbol, activity); err != nil {
+			log.Fatalf("%s: %v", symbol, err)
+		}
+		fmt.Printf("%s: %d sessions\n", symbol, n)
+	}
+}
@@ -4,27 +4,40 @@ import (
 	"fmt"
 	gbt "github.com/dirkolbrich/gobacktest"
 	"github.com/dirkolbrich/gobacktest/algo"
-	"github.com/dirkolbrich/gobacktest/data"
-	"io/ioutil"
 	"log"
+	"os"
+	"pkg/btfeed"
 	"pkg/cfg"
-	"strings"
+	"pkg/quotes"
+	"pkg/symbols"
+	"pkg/universe"
 )
 
+// bt <universe> backtests the strategies on every symbol that was ever in
+// the universe, trading each only while it was a member
 func main() {
-	_, dataFolder := cfg.GetConfiguration()
-	symbols := getSymbols(dataFolder)
+	if len(os.Args) < 2 {
+		log.Fatal("Usage: bt <universe>")
+	}
+	master = symbols.LoadOrEmpty(cfg.SymbolMaster(), "NSE")
+	members, err := universe.Load(universe.File(cfg.Universes(), os.Args[1]), master)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Universe %s: %d symbols from %s\n", members.Name, len(members.Symbols()), members.Window)
+	filter := cfg.Filter()
+	filter.Master = master
 	pl1 := 0.00
 	pl2 := 0.00
-	for _, symbol := range symbols {
+	for _, symbol := range members.Symbols() {
 		fmt.Printf("***************** %s *******************\n", symbol)
-		pl1 += testEma(symbol)
-		pl2 += testEmaConfirmedByMfi(symbol)
+		pl1 += testEma(symbol, members, filter)
+		pl2 += testEmaConfirmedByMfi(symbol, members, filter)
 	}
 	fmt.Printf("Total PL: %.2f (EMA) %.2f (EMA+MFI)\n", pl1, pl2)
 }
 
-func testEma(symbol string) float64 {
+func testEma(symbol string, members *universe.Universe, filter *universe.Filter) float64 {
 	cfg, dataFolder := cfg.GetConfiguration()
 	// initiate a new backtester
 	test := gbt.New()
@@ -34,31 +47,28 @@ func testEma(symbol string) float64 {
 	test.SetSymbols(symbols)
 
 	// create a data provider and load the data into the backtest
-	mydata := &data.BarEventFromCSVFile{FileDir: "./" + dataFolder + "/"}
-	mydata.Load(symbols)
+	mydata := btfeed.New(pipeline(dataFolder))
+	if err := mydata.Load(symbols); err != nil {
+		log.Print(err)
+		return 0
+	}
 	test.SetData(mydata)
 
 	// create a new strategy with an algo stack and load into the backtest
 	strategy := gbt.NewStrategy("basic")
-	period := algo.RunMonthly()
-	switch {
-	case cfg.Period() == 1:
-		period = algo.RunDaily()
-	case cfg.Period() < 22:
-		period = algo.RunWeekly()
-	case cfg.Period() < 64:
-		period = algo.RunMonthly()
-	case cfg.Period() < 200:
-		period = algo.RunQuarterly()
-	default:
-		period = algo.RunYearly()
-	}
+	period := schedule(cfg.Period())
 	strategy.SetAlgo(
 		period,
 		algo.If(
 			// condition
 			algo.And(
-				algo.BiggerThan(algo.EMA(50), algo.EMA(200)),
+				algo.And(
+					algo.And(
+						btfeed.Member(members),
+						btfeed.Tradable(mydata, filter),
+					),
+					algo.BiggerThan(btfeed.EMA(mydata, 50), btfeed.EMA(mydata, 200)),
+				),
 				algo.NotInvested(),
 			),
 			// action
@@ -68,8 +78,11 @@ func testEma(symbol string) float64 {
 			// condition
 			algo.And(
 				algo.Or(
-					algo.HasHitStopLoss(),
-					algo.SmallerThan(algo.EMA(50), algo.EMA(200)),
+					algo.Or(
+						algo.HasHitStopLoss(),
+						btfeed.NotMember(members),
+					),
+					algo.SmallerThan(btfeed.EMA(mydata, 50), btfeed.EMA(mydata, 200)),
 				),
 				algo.IsInvested(),
 			),
@@ -85,7 +98,7 @@ func testEma(symbol string) float64 {
 	p.SetInitialCash(cfg.InitialCash())
 
 	// create an asset and append to strategy
-	strategy.SetChildren(gbt.NewAsset("BAJFINANCE.NS"))
+	strategy.SetChildren(gbt.NewAsset(symbol))
 
 	// load the strategy into the backtest
 	test.SetStrategy(strategy)
@@ -121,7 +134,7 @@ func testEma(symbol string) float64 {
 	return pl
 }
 
-func testEmaConfirmedByMfi(symbol string) float64 {
+func testEmaConfirmedByMfi(symbol string, members *universe.Universe, filter *universe.Filter) float64 {
 	cfg, dataFolder := cfg.GetConfiguration()
 	// initiate a new backtester
 	test := gbt.New()
@@ -131,33 +144,30 @@ func testEmaConfirmedByMfi(symbol string) float64 {
 	test.SetSymbols(symbols)
 
 	// create a data provider and load the data into the backtest
-	mydata := &data.BarEventFromCSVFile{FileDir: "./" + dataFolder + "/"}
-	mydata.Load(symbols)
+	mydata := btfeed.New(pipeline(dataFolder))
+	if err := mydata.Load(symbols); err != nil {
+		log.Print(err)
+		return 0
+	}
 	test.SetData(mydata)
 
 	// create a new strategy with an algo stack and load into the backtest
 	strategy := gbt.NewStrategy("basic")
-	period := algo.RunMonthly()
-	switch {
-	case cfg.Period() == 1:
-		period = algo.RunDaily()
-	case cfg.Period() < 22:
-		period = algo.RunWeekly()
-	case cfg.Period() < 64:
-		period = algo.RunMonthly()
-	case cfg.Period() < 200:
-		period = algo.RunQuarterly()
-	default:
-		period = algo.RunYearly()
-	}
+	period := schedule(cfg.Period())
 	strategy.SetAlgo(
 		period,
 		algo.If(
 			// condition
 			algo.And(
 				algo.And(
-					algo.BiggerThan(algo.EMA(50), algo.EMA(200)),
-					algo.BiggerThan(algo.Mfi(10), algo.Number(50)),
+					algo.And(
+						algo.And(
+							btfeed.Member(members),
+							btfeed.Tradable(mydata, filter),
+						),
+						algo.BiggerThan(btfeed.EMA(mydata, 50), btfeed.EMA(mydata, 200)),
+					),
+					algo.BiggerThan(btfeed.Mfi(mydata, 10), algo.Number(50)),
 				),
 				algo.NotInvested(),
 			),
@@ -168,12 +178,15 @@ func testEmaConfirmedByMfi(symbol string) float64 {
 			// condition
 			algo.And(
 				algo.Or(
-					algo.HasHitStopLoss(),
+					algo.Or(
+						algo.HasHitStopLoss(),
+						btfeed.NotMember(members),
+					),
 					algo.Or(
 						algo.HasHitTarget(),
 						algo.And(
-							algo.SmallerThan(algo.EMA(50), algo.EMA(200)),
-							algo.SmallerThan(algo.Mfi(10), algo.Number(50)),
+							algo.SmallerThan(btfeed.EMA(mydata, 50), btfeed.EMA(mydata, 200)),
+							algo.SmallerThan(btfeed.Mfi(mydata, 10), algo.Number(50)),
 						),
 					),
 				),
@@ -191,7 +204,7 @@ func testEmaConfirmedByMfi(symbol string) float64 {
 	p.SetInitialCash(cfg.InitialCash())
 
 	// create an asset and append to strategy
-	strategy.SetChildren(gbt.NewAsset("BAJFINANCE.NS"))
+	strategy.SetChildren(gbt.NewAsset(symbol))
 
 	// load the strategy into the backtest
 	test.SetStrategy(strategy)
@@ -227,19 +240,42 @@ func testEmaConfirmedByMfi(symbol string) float64 {
 	return pl
 }
 
-func getSymbols(dataFolder string) []string {
-	files, err := ioutil.ReadDir(dataFolder)
-	if err != nil {
-		log.Fatal(err)
+// pipeline loads the quotes of the data folder, dropping unusable sessions
+// schedule returns the algo rebalancing every given number of sessions: on
+// the first session of the week, month or quarter of the NSE calendar, so a
+// Monday holiday moves the run to the Tuesday. Without a holiday list the
+// periods start on the first bar of a new week, month or quarter.
+func schedule(days int) gbt.AlgoHandler {
+	switch {
+	case days == 1:
+		return algo.RunDaily()
+	case days >= 200:
+		return algo.RunYearly()
 	}
-	fmt.Printf("Found %d files in '%s'\n", len(files), dataFolder)
-	symbols := []string{}
-	for _, file := range files {
-		tokens := strings.Split(file.Name(), ".")
-		if len(tokens) != 2 || tokens[1] != "csv" {
-			continue
+	nse, err := cfg.Calendar()
+	if err != nil {
+		log.Printf("No holiday list, rebalancing on calendar periods: %v", err)
+		switch {
+		case days < 22:
+			return algo.RunWeekly()
+		case days < 64:
+			return algo.RunMonthly()
 		}
-		symbols = append(symbols, tokens[0])
+		return algo.RunQuarterly()
+	}
+	switch {
+	case days < 22:
+		return btfeed.RunWeekly(nse)
+	case days < 64:
+		return btfeed.RunMonthly(nse)
 	}
-	return symbols
+	return btfeed.RunQuarterly(nse)
 }
+
+func pipeline(dataFolder string) *quotes.Pipeline {
+	p := cfg.Pipeline(dataFolder, master)
+	p.Repair = quotes.RepairDrop
+	return p
+}
+
+var master *symbols.Master // loaded by main
@@ -2,12 +2,15 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"sort"
 	"time"
 
 	"github.com/dirkolbrich/gobacktest"
-	"github.com/dirkolbrich/gobacktest/data"
 	"github.com/dirkolbrich/gobacktest/strategy"
+
+	"pkg/btfeed"
+	"pkg/cfg"
 )
 
 // Result bundles the result of a single backtest
@@ -37,8 +40,10 @@ func main() {
 	test.SetSymbols(symbols)
 
 	// create data provider and load data into the backtest
-	data := &data.BarEventFromCSVFile{FileDir: "testdata/"}
-	data.Load(symbols)
+	data := btfeed.New(cfg.Pipeline("testdata", nil))
+	if err := data.Load(symbols); err != nil {
+		log.Fatal(err)
+	}
 	test.SetData(data)
 
 	startTest := time.Now()
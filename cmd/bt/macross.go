@@ -4,7 +4,9 @@ import (
 	"fmt"
 	gbt "github.com/dirkolbrich/gobacktest"
 	"github.com/dirkolbrich/gobacktest/algo"
-	"github.com/dirkolbrich/gobacktest/data"
+	"log"
+	"pkg/btfeed"
+	"pkg/cfg"
 )
 
 func main() {
@@ -16,8 +18,10 @@ func main() {
 	test.SetSymbols(symbols)
 
 	// create data provider and load data into the backtest
-	data := &data.BarEventFromCSVFile{FileDir: "testdata/"}
-	data.Load(symbols)
+	data := btfeed.New(cfg.Pipeline("testdata", nil))
+	if err := data.Load(symbols); err != nil {
+		log.Fatal(err)
+	}
 	test.SetData(data)
 
 	// create a new strategy with an algo stack and load into the backtest
@@ -27,7 +31,7 @@ func main() {
 		algo.If(
 			// condition
 			algo.And(
-				algo.BiggerThan(algo.SMA(50), algo.SMA(200)),
+				algo.BiggerThan(btfeed.SMA(data, 50), btfeed.SMA(data, 200)),
 				algo.NotInvested(),
 			),
 			// action
@@ -36,7 +40,7 @@ func main() {
 		algo.If(
 			// condition
 			algo.And(
-				algo.SmallerThan(algo.SMA(50), algo.SMA(200)),
+				algo.SmallerThan(btfeed.SMA(data, 50), btfeed.SMA(data, 200)),
 				algo.IsInvested(),
 			),
 			// action
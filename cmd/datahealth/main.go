@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"pkg/cfg"
+	"pkg/quotes"
+	"pkg/symbols"
+	"strings"
+)
+
+// datahealth prints a data-health report for every quote file in the data
+// folder, or in the folder given as the first argument. With -v every issue
+// is listed.
+func main() {
+	_, dataFolder := cfg.GetConfiguration()
+	verbose := false
+	for _, arg := range os.Args[1:] {
+		if arg == "-v" {
+			verbose = true
+		} else {
+			dataFolder = arg
+		}
+	}
+	files, err := ioutil.ReadDir(dataFolder)
+	if err != nil {
+		log.Fatal(err)
+	}
+	validator := cfg.Validator()
+	master := symbols.LoadOrEmpty(cfg.SymbolMaster(), "NSE")
+	healthy, total := 0, 0
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".csv" {
+			continue
+		}
+		symbol := master.Ticker(strings.TrimSuffix(file.Name(), ".csv"))
+		total++
+		qd, loadReport, err := quotes.LoadFileWithSchema(filepath.Join(dataFolder, file.Name()), symbol, nil)
+		if err != nil {
+			fmt.Printf("%s: %v\n", symbol, err)
+			continue
+		}
+		report := validator.Validate(qd)
+		if len(loadReport.Skipped) == 0 && len(report.Issues) == 0 {
+			healthy++
+		}
+		fmt.Println(report)
+		if len(loadReport.Skipped) > 0 {
+			fmt.Printf("  %d unreadable rows\n", len(loadReport.Skipped))
+		}
+		if verbose {
+			for _, l := range loadReport.Skipped {
+				fmt.Printf("  skipped %s\n", l)
+			}
+			for _, i := range report.Issues {
+				fmt.Printf("  %s\n", i)
+			}
+		}
+	}
+	fmt.Printf("%d of %d files healthy\n", healthy, total)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"pkg/quotes"
+	"strings"
+)
+
+// quotemerge merges a fresh download into an existing quote file, keeping
+// one row per date and the file's own format. Conflicting rows are listed
+// and resolved by the policy: newest (default), existing or fail.
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: quotemerge <quote file> <new download> [newest|existing|fail]")
+		os.Exit(2)
+	}
+	file, newfile := os.Args[1], os.Args[2]
+	policy := quotes.PreferNewest
+	if len(os.Args) > 3 {
+		switch os.Args[3] {
+		case "newest":
+			policy = quotes.PreferNewest
+		case "existing":
+			policy = quotes.PreferExisting
+		case "fail":
+			policy = quotes.FailOnConflict
+		default:
+			log.Fatalf("unknown conflict policy %q", os.Args[3])
+		}
+	}
+	symbol := strings.Split(filepath.Base(file), ".")[0]
+	report, err := quotes.MergeFile(file, newfile, symbol, policy)
+	if report != nil {
+		fmt.Println(report)
+		for _, c := range report.Conflicts {
+			fmt.Printf("  %s\n", c)
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
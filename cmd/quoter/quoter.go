@@ -3,12 +3,15 @@ package main
 import (
 	//	"github.com/vdobler/chart"
 	"fmt"
-	"github.com/jinzhu/now"
 	"io/ioutil"
 	"log"
 	"os"
+	"pkg/cfg"
+	"pkg/indicator"
 	"pkg/quotes"
+	"pkg/symbols"
 	"pkg/talib"
+	"pkg/universe"
 	"strconv"
 	"strings"
 	"time"
@@ -30,32 +33,76 @@ func main() {
 	mm.RiskOnTrade, _ = strconv.ParseFloat(os.Args[2], 64)
 	mm.ProfitOnTrade, _ = strconv.ParseFloat(os.Args[3], 64)
 	mm.RiskOnCapital, _ = strconv.ParseFloat(os.Args[4], 64)
-	symbols := getSymbols()
+	master := symbols.LoadOrEmpty(cfg.SymbolMasterOrDefault(), "NSE")
 	var totalProfit float64 = 0
 
-	for _, symbol := range symbols {
+	pipeline := cfg.Pipeline("", master)
+	pipeline.Ext = ".aqh"
+	filter := tradingFilter()
+	filter.Master = master
+	var data []*quotes.QuoteData
+	for _, symbol := range getSymbols(master) {
 		//	check(symbol)
-		log.Printf("**********  %s   ***********", symbol)
-		qtd := quotes.LoadFromFile(symbol+".NS.aqh", symbol, 3)
-		totalProfit += BackTestMovingAverages(qtd, &mm)
+		qtd, report, err := pipeline.Load(symbol)
+		if err != nil {
+			log.Fatalf("%s: %v", symbol, err)
+		}
+		if !report.Clean() {
+			log.Print(report.Details())
+		}
+		data = append(data, qtd)
+	}
+	if filter.MinRSRank > 0 {
+		filter.Ranks = rsRanks(pipeline, data)
+	}
+	for _, qtd := range data {
+		log.Printf("**********  %s   ***********", qtd.Symbol)
+		totalProfit += BackTestMovingAverages(qtd, &mm, filter)
 	}
 	log.Printf("Total profit: %.0f", totalProfit)
 }
 
-func getSymbols() []string {
+// tradingFilter returns the filter of config.yaml, or only the penny stock
+// check when the current folder has no configuration
+func tradingFilter() *universe.Filter {
+	if !cfg.HasConfiguration() {
+		log.Print("No config.yaml, filtering out closes below 20 only")
+		return &universe.Filter{MinPrice: 20}
+	}
+	return cfg.Filter()
+}
+
+// rsRanks ranks the symbols by their strength against the benchmark of
+// config.yaml, whose quotes sit with theirs
+func rsRanks(pipeline *quotes.Pipeline, data []*quotes.QuoteData) *quotes.RSRanks {
+	benchmark := cfg.Benchmark()
+	bench, _, err := pipeline.Load(benchmark)
+	if err != nil {
+		log.Fatalf("filter.minRSRank needs the benchmark %s: %v; name another symbol with the benchmark key of config.yaml",
+			benchmark, err)
+	}
+	rels := make([]*quotes.Relative, len(data))
+	for i, qd := range data {
+		rels[i] = quotes.NewRelative(qd, bench, quotes.DailyRSPeriods)
+	}
+	return quotes.NewRSRanks(rels)
+}
+
+// getSymbols returns the tickers of the .aqh files in the current folder,
+// which may be named after any name the master knows the symbol by
+func getSymbols(master *symbols.Master) []string {
 	files, err := ioutil.ReadDir(".")
 	if err != nil {
 		log.Fatal(err)
 	}
-	symbols := []string{}
+	tickers := []string{}
 	for _, file := range files {
-		tokens := strings.Split(file.Name(), ".")
-		if len(tokens) != 3 || tokens[2] != "aqh" {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".aqh") {
 			continue
 		}
-		symbols = append(symbols, tokens[0])
+		tickers = append(tickers, master.Ticker(strings.TrimSuffix(file.Name(), ".aqh")))
 	}
-	return symbols
+	return tickers
 }
 
 /*
@@ -96,26 +143,55 @@ func BearishCrossover(qtd *quotes.QuoteData, ema20 []float64, ema50 []float64, i
 	return e20 < e50 && e20a > e50a && qtd.Closes[index] < e20
 }
 
-func BackTestMovingAverages(qtd *quotes.QuoteData, mm *MoneyManagement) float64 {
+// movingAverages are the indicators BackTestMovingAverages trades on: the
+// 20 and 50 session averages and the 20 session aroon
+var movingAverages = []indicator.Spec{
+	{Name: "SMA", Params: indicator.Params{"timeperiod": 20}},
+	{Name: "SMA", Params: indicator.Params{"timeperiod": 50}},
+	{Name: "AROON", Params: indicator.Params{"timeperiod": 20}},
+}
+
+// BackTestMovingAverages trades the crossovers of the quotes, entering only
+// on days the symbol passes the filter
+func BackTestMovingAverages(qtd *quotes.QuoteData, mm *MoneyManagement, filter *universe.Filter) float64 {
 	var tradebook []*Trade = []*Trade{}
 	var position *Trade = nil
 	totalCloses := len(qtd.Closes)
-	ema20 := talib.Sma(qtd.Closes, 20)
-	ema50 := talib.Sma(qtd.Closes, 50)
-	aroonUp, aroonDn := talib.Aroon(qtd.Highs, qtd.Lows, 20)
+	series := make([]map[string][]float64, len(movingAverages))
+	for k, spec := range movingAverages {
+		outputs, err := spec.ComputeNaN(qtd)
+		if err != nil {
+			log.Printf("%s: %v", qtd.Symbol, err)
+			return 0
+		}
+		series[k] = outputs
+	}
+	ema20, ema50 := series[0]["real"], series[1]["real"]
+	aroonDn, aroonUp := series[2]["aroondown"], series[2]["aroonup"]
+	var avgDelivery []float64
+	if qtd.Has(quotes.DeliveryPct) {
+		avgDelivery = talib.Sma(qtd.DeliveryPcts, 20)
+	}
 	tradingCap := mm.Capital
+	warmUp, err := indicator.WarmUp(movingAverages...)
+	if err != nil {
+		log.Printf("%s: %v", qtd.Symbol, err)
+		return 0
+	}
 
 	for i, todayclose := range qtd.Closes {
-		if todayclose < 20 {
-			continue
-		}
-		if i <= 200 { // not the first 200 days
+		if i <= warmUp { // the crossovers read the session before too
 			continue
 		}
 		if i+1 == totalCloses {
 			continue
 		}
-		if position == nil && BullishCrossover(qtd, ema20, ema50, i) && IsTrendingUp(aroonUp[i], aroonDn[i]) {
+		if position == nil && BullishCrossover(qtd, ema20, ema50, i) && IsTrendingUp(aroonUp[i], aroonDn[i]) &&
+			IsDeliveryConfirmed(qtd, avgDelivery, i) {
+			if failed := filter.Exclusions(qtd, i); len(failed) > 0 {
+				log.Printf("%s excluded on %s: %s", qtd.Symbol, qtd.Dates[i].Format("2006-01-02"), universe.Explain(failed))
+				continue
+			}
 			price := qtd.Highs[i+1]
 			size := CalculateTradeSize(tradingCap, price, price*mm.RiskOnTrade, mm.RiskOnCapital)
 			if size < 1 { // insufficient capital
@@ -174,6 +250,15 @@ func IsTrendingUp(aroonUp float64, aroonDn float64) bool {
 	return aroonUp > 70 && aroonDn < 40
 }
 
+// IsDeliveryConfirmed returns true when the delivery percentage of the day
+// is above its average, or when the quotes have no delivery data
+func IsDeliveryConfirmed(qtd *quotes.QuoteData, avgDelivery []float64, index int) bool {
+	if !qtd.Has(quotes.DeliveryPct) {
+		return true
+	}
+	return qtd.DeliveryPcts[index] > avgDelivery[index]
+}
+
 func HasTrendReversed(aroonUp float64, aroonDn float64) bool {
 	return aroonUp < 50 && aroonDn > 25
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"pkg/quotes"
+	"pkg/talib"
+	"testing"
+	"time"
+)
+
+func TestBullishCrossover(t *testing.T) {
+	g := &quotes.Generator{Symbol: "SYNTH", Start: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Seed: 1}
+	for _, at := range []int{60, 210, 280} {
+		qtd := g.FromCloses(quotes.CrossoverCloses(20, 50, at, 300, 500))
+		ema20 := talib.Sma(qtd.Closes, 20)
+		ema50 := talib.Sma(qtd.Closes, 50)
+		aroonDn, aroonUp := talib.Aroon(qtd.Highs, qtd.Lows, 20)
+		for i := 51; i < len(qtd.Closes); i++ {
+			if got := BullishCrossover(qtd, ema20, ema50, i); got != (i == at) {
+				t.Errorf("crossover at %d: BullishCrossover on %d is %v", at, i, got)
+			}
+			if BearishCrossover(qtd, ema20, ema50, i) {
+				t.Errorf("crossover at %d: BearishCrossover on %d", at, i)
+			}
+		}
+		if !IsTrendingUp(aroonUp[at], aroonDn[at]) {
+			t.Errorf("crossover at %d: not trending up, aroon up %.0f down %.0f", at, aroonUp[at], aroonDn[at])
+		}
+	}
+}
+
+func TestIsTrendingUp(t *testing.T) {
+	g := &quotes.Generator{Symbol: "SYNTH", Start: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Seed: 1, Price: 100}
+	up := g.Generate(quotes.GBM{Drift: 3, Volatility: 0.05}, 100)
+	aroonDn, aroonUp := talib.Aroon(up.Highs, up.Lows, 20)
+	if !IsTrendingUp(aroonUp[99], aroonDn[99]) {
+		t.Errorf("rally: aroon up %.0f down %.0f", aroonUp[99], aroonDn[99])
+	}
+	down := g.Generate(quotes.GBM{Drift: -3, Volatility: 0.05}, 100)
+	aroonDn, aroonUp = talib.Aroon(down.Highs, down.Lows, 20)
+	if IsTrendingUp(aroonUp[99], aroonDn[99]) || !HasTrendReversed(aroonUp[99], aroonDn[99]) {
+		t.Errorf("decline: aroon up %.0f down %.0f", aroonUp[99], aroonDn[99])
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"pkg/cfg"
+	"pkg/store"
+	"pkg/symbols"
+	"strings"
+	"time"
+)
+
+const usage = `Usage:
+  quotestore import <file or folder>...   upsert csv/aqh quote files
+  quotestore symbols                      list symbols, sector, date coverage and rows
+  quotestore show <symbol> [from [to]]    print quotes, dates as yyyy-mm-dd`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	db, err := store.Open(cfg.QuoteStore())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	master := symbols.LoadOrEmpty(cfg.SymbolMaster(), "NSE")
+
+	switch os.Args[1] {
+	case "import":
+		for _, arg := range os.Args[2:] {
+			importPath(db, master, arg)
+		}
+	case "symbols":
+		coverage, err := db.Symbols()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, c := range coverage {
+			fmt.Printf("%-15s %-25s %s %s %6d\n", c.Symbol, master.Resolve(c.Symbol).Sector,
+				c.First.Format("2006-01-02"), c.Last.Format("2006-01-02"), c.Rows)
+		}
+	case "show":
+		if len(os.Args) < 3 {
+			fmt.Println(usage)
+			os.Exit(2)
+		}
+		var from, to time.Time
+		if len(os.Args) > 3 {
+			from = parseDate(os.Args[3])
+		}
+		if len(os.Args) > 4 {
+			to = parseDate(os.Args[4])
+		}
+		qd, err := db.Load(master.Ticker(os.Args[2]), from, to)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, d := range qd.Dates {
+			fmt.Printf("%s %10.2f %10.2f %10.2f %10.2f %12.0f\n", d.Format("2006-01-02"),
+				qd.Opens[i], qd.Highs[i], qd.Lows[i], qd.Closes[i], qd.Volumes[i])
+		}
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
+
+func parseDate(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return d
+}
+
+// importPath imports a quote file, or every csv and aqh file in a folder
+func importPath(db *store.Store, master *symbols.Master, path string) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !fi.IsDir() {
+		importFile(db, master, path)
+		return
+	}
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if file.IsDir() || (ext != ".csv" && ext != ".aqh") {
+			continue
+		}
+		importFile(db, master, filepath.Join(path, file.Name()))
+	}
+}
+
+func importFile(db *store.Store, master *symbols.Master, file string) {
+	// BAJFINANCE.csv, BAJFINANCE.NS.csv and BAJFINANCE.NS.aqh all hold BAJFINANCE
+	symbol := master.Ticker(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)))
+	n, report, err := db.ImportFile(file, symbol)
+	if err != nil {
+		log.Printf("%s: %v", file, err)
+		return
+	}
+	fmt.Printf("%s: %d rows from %s (%s)\n", symbol, n, file, report)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"pkg/cfg"
+	"pkg/quotes"
+	"pkg/symbols"
+	"pkg/universe"
+	"sort"
+	"strings"
+)
+
+// rs [benchmark] [-w] [-csv file] ranks the symbols of the data folder by
+// their strength against the benchmark and shows where each stands on the
+// relative rotation graph. Symbols the liquidity filter excludes on the
+// last session are listed apart with the reasons. -w works on weekly bars;
+// -csv writes every session to the file as well.
+func main() {
+	_, dataFolder := cfg.GetConfiguration()
+	benchmark := cfg.Benchmark()
+	weekly := false
+	csvfile := ""
+	for i := 1; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "-w":
+			weekly = true
+		case "-csv":
+			if i+1 == len(os.Args) {
+				log.Fatal("Usage: rs [benchmark] [-w] [-csv file]")
+			}
+			i++
+			csvfile = os.Args[i]
+		default:
+			benchmark = os.Args[i]
+		}
+	}
+	master := symbols.LoadOrEmpty(cfg.SymbolMaster(), "NSE")
+	pipeline := cfg.Pipeline(dataFolder, master)
+	pipeline.Mode = quotes.AdjustedPrices
+	periods := quotes.DailyRSPeriods
+	load := func(symbol string) *quotes.QuoteData {
+		qd, _, err := pipeline.Load(symbol)
+		if err != nil {
+			log.Fatalf("%s: %v", symbol, err)
+		}
+		return qd
+	}
+	bars := func(qd *quotes.QuoteData) *quotes.QuoteData {
+		if weekly {
+			return qd.Resample(quotes.Weekly)
+		}
+		return qd
+	}
+	if weekly {
+		periods = quotes.RSPeriods{Mansfield: 52, RRG: 14}
+	}
+	bench, _, err := pipeline.Load(benchmark)
+	if err != nil {
+		log.Fatalf("benchmark %s: %v; put its quotes in %s or name another symbol with the benchmark key of config.yaml",
+			benchmark, err, dataFolder)
+	}
+	benchmarks := &quotes.Benchmarks{Default: bars(bench)}
+	filter := cfg.Filter()
+	filter.Master = master
+	// the ranks are what rs works out; the other checks pick the symbols ranked
+	filter.MinRSRank = 0
+
+	files, err := ioutil.ReadDir(dataFolder)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var daily []*quotes.QuoteData
+	for _, file := range files {
+		symbol := master.Ticker(strings.TrimSuffix(file.Name(), ".csv"))
+		if file.IsDir() || filepath.Ext(file.Name()) != ".csv" || symbol == master.Ticker(benchmark) {
+			continue
+		}
+		daily = append(daily, load(symbol))
+	}
+	if len(daily) == 0 {
+		log.Fatalf("no symbols in %s", dataFolder)
+	}
+
+	last := benchmarks.Default.Dates[len(benchmarks.Default.Dates)-1]
+	_, excluded := filter.Screen(daily, last)
+	var rels []*quotes.Relative
+	for _, qd := range daily {
+		if _, ok := excluded[qd.Symbol]; !ok {
+			rels = append(rels, benchmarks.Relative(bars(qd), periods))
+		}
+	}
+	ranks := quotes.RankRS(rels, last)
+	sort.Slice(rels, func(i, j int) bool { return ranks[rels[i].Symbol] > ranks[rels[j].Symbol] })
+	fmt.Printf("Relative strength against %s on %s\n", benchmark, last.Format("2006-01-02"))
+	for _, r := range rels {
+		i := len(r.Dates) - 1
+		fmt.Printf("%-12s %-25s rank:%3.0f ratio:%8.2f mansfield:%7.2f rs-ratio:%7.2f rs-momentum:%7.2f %s\n",
+			r.Symbol, master.Resolve(r.Symbol).Sector, ranks[r.Symbol], r.Ratio[i], r.Mansfield[i], r.RSRatio[i], r.RSMomentum[i], r.Quadrant(i))
+	}
+	if len(excluded) > 0 {
+		fmt.Printf("Excluded by the liquidity filter\n")
+	}
+	for _, qd := range daily {
+		if failed, ok := excluded[qd.Symbol]; ok {
+			fmt.Printf("%-12s %s\n", qd.Symbol, universe.Explain(failed))
+		}
+	}
+
+	if csvfile != "" {
+		f, err := os.Create(csvfile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		if err := quotes.WriteRelative(f, rels...); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
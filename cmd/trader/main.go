@@ -3,8 +3,10 @@ package main
 import (
 	"log"
 	"os"
+	"pkg/cfg"
 	"pkg/mm"
 	"pkg/quotes"
+	"pkg/symbols"
 	"strconv"
 	"time"
 )
@@ -13,10 +15,17 @@ func main() {
 	portfolioHistory := []*mm.Portfolio{}
 	tradeHistory := []*mm.TradeHistoryEntry{}
 	investment, _ := strconv.ParseFloat(os.Args[1], 64)
+	master = symbols.LoadOrEmpty(cfg.SymbolMasterOrDefault(), "NSE")
+	pipeline = cfg.Pipeline("", master)
+	pipeline.Ext = ".aqh"
 	portfolio := mm.NewPortfolio(investment, investment)
+	portfolio.Symbols = master
 	trades := mm.LoadTrades("trades.csv")
 	for _, t := range trades {
-		high, low := GetChannel(t.Symbol, t.Date)
+		high, low, err := GetChannel(t.Symbol, t.Date)
+		if err != nil {
+			log.Fatalf("%s: %v", t.Symbol, err)
+		}
 		t.Execute(t.Price, t.Size, high, low) // grades the execution
 		if t.Action == mm.Buy {
 			portfolio.Buy(t.Symbol, *t)
@@ -49,31 +58,30 @@ func main() {
 
 var quotecache map[string]*quotes.QuoteData = make(map[string]*quotes.QuoteData, 1)
 
-func GetChannel(symbol string, date time.Time) (high float64, low float64) {
+// master and pipeline are set up by main
+var master *symbols.Master
+
+var pipeline *quotes.Pipeline
+
+func GetChannel(symbol string, date time.Time) (high float64, low float64, err error) {
+	symbol = master.Ticker(symbol)
 	a, ok := quotecache[symbol]
 	if ok == false {
-		a = quotes.LoadFromFile(symbol+".NS.aqh", symbol, 3)
-		if a != nil {
-			quotecache[symbol] = a
+		var report *quotes.LoadReport
+		a, report, err = pipeline.Load(symbol)
+		if err != nil {
+			return 0, 0, err
 		}
-	}
-	if a == nil {
-		return 0, 0
-	}
-	found := -1
-	if len(a.Dates) == 0 {
-		return 0, 0
-	}
-	for i, v := range a.Dates {
-		if v == date {
-			found = i
-			break
+		if !report.Clean() {
+			log.Print(report.Details())
 		}
+		quotecache[symbol] = a
 	}
+	found := a.Index(date, quotes.Exact)
 	if found == -1 {
-		return 0, 0
+		return 0, 0, nil
 	}
 	high = a.Highs[found]
 	low = a.Lows[found]
-	return high, low
+	return high, low, nil
 }
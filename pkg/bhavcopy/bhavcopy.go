@@ -0,0 +1,374 @@
+// Package bhavcopy reads NSE daily bhavcopy files, which hold every symbol
+// traded on one date, and turns them into per-symbol histories
+package bhavcopy
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"pkg/calendar"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is one symbol's session in a bhavcopy
+type Record struct {
+	Symbol    string
+	Series    string
+	ISIN      string
+	Date      time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Last      float64
+	PrevClose float64
+	Volume    float64 // total traded quantity
+	Turnover  float64 // total traded value in rupees
+	Trades    float64
+	Delivery  float64 // deliverable quantity, NaN when unknown
+}
+
+// DefaultSeries are the series imported when none are given: rolling
+// settlement equities and trade-for-trade ones
+var DefaultSeries = []string{"EQ", "BE"}
+
+type field int
+
+const (
+	fDate field = iota
+	fSymbol
+	fSeries
+	fISIN
+	fOpen
+	fHigh
+	fLow
+	fClose
+	fLast
+	fPrevClose
+	fVolume
+	fTurnover
+	fTurnoverLacs
+	fTrades
+	fDelivery
+	fieldCount
+)
+
+// fieldNames maps the column names of the classic cm bhavcopy, the full
+// bhavcopy with delivery data and the UDiFF bhavcopy to record fields
+var fieldNames = map[string]field{
+	"TIMESTAMP":       fDate,
+	"DATE1":           fDate,
+	"TRADDT":          fDate,
+	"SYMBOL":          fSymbol,
+	"TCKRSYMB":        fSymbol,
+	"SERIES":          fSeries,
+	"SCTYSRS":         fSeries,
+	"ISIN":            fISIN,
+	"OPEN":            fOpen,
+	"OPEN_PRICE":      fOpen,
+	"OPNPRIC":         fOpen,
+	"HIGH":            fHigh,
+	"HIGH_PRICE":      fHigh,
+	"HGHPRIC":         fHigh,
+	"LOW":             fLow,
+	"LOW_PRICE":       fLow,
+	"LWPRIC":          fLow,
+	"CLOSE":           fClose,
+	"CLOSE_PRICE":     fClose,
+	"CLSPRIC":         fClose,
+	"LAST":            fLast,
+	"LAST_PRICE":      fLast,
+	"LASTPRIC":        fLast,
+	"PREVCLOSE":       fPrevClose,
+	"PREV_CLOSE":      fPrevClose,
+	"PRVSCLSGPRIC":    fPrevClose,
+	"TOTTRDQTY":       fVolume,
+	"TTL_TRD_QNTY":    fVolume,
+	"TTLTRADGVOL":     fVolume,
+	"TOTTRDVAL":       fTurnover,
+	"TTLTRFVAL":       fTurnover,
+	"TURNOVER_LACS":   fTurnoverLacs,
+	"TOTALTRADES":     fTrades,
+	"NO_OF_TRADES":    fTrades,
+	"TTLNBOFTXSEXCTD": fTrades,
+	"DELIV_QTY":       fDelivery,
+}
+
+var dateLayouts = []string{"02-Jan-2006", "2006-01-02", "02-01-2006", "02Jan2006"}
+
+// parseNumber reads a number; blanks and dashes, used for missing
+// delivery data, give NaN
+func parseNumber(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "-" {
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// Read reads the records of a bhavcopy. Delivery position (MTO) files are
+// recognised too and give records holding only the deliverable quantity.
+func Read(r io.Reader) ([]Record, error) {
+	br := bufio.NewReader(r)
+	first, err := br.Peek(64)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if strings.HasPrefix(string(first), "Security Wise Delivery Position") {
+		return readDelivery(br)
+	}
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]int, fieldCount)
+	for i := range columns {
+		columns[i] = -1
+	}
+	for i, h := range header {
+		if f, ok := fieldNames[strings.ToUpper(strings.TrimSpace(h))]; ok {
+			columns[f] = i
+		}
+	}
+	for _, f := range []field{fDate, fSymbol, fSeries, fOpen, fHigh, fLow, fClose} {
+		if columns[f] < 0 {
+			return nil, fmt.Errorf("not a bhavcopy, header %v", header)
+		}
+	}
+	var records []Record
+	for line := 2; ; line++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		rec, err := parseRecord(fields, columns)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func parseRecord(fields []string, columns []int) (Record, error) {
+	get := func(f field) string {
+		if columns[f] < 0 || columns[f] >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[columns[f]])
+	}
+	rec := Record{Symbol: get(fSymbol), Series: get(fSeries), ISIN: get(fISIN)}
+	var err error
+	if rec.Date, err = calendar.ParseDate(get(fDate), dateLayouts...); err != nil {
+		return rec, err
+	}
+	numbers := []struct {
+		f field
+		v *float64
+	}{
+		{fOpen, &rec.Open}, {fHigh, &rec.High}, {fLow, &rec.Low}, {fClose, &rec.Close},
+		{fLast, &rec.Last}, {fPrevClose, &rec.PrevClose}, {fVolume, &rec.Volume},
+		{fTurnover, &rec.Turnover}, {fTrades, &rec.Trades}, {fDelivery, &rec.Delivery},
+	}
+	for _, n := range numbers {
+		if *n.v, err = parseNumber(get(n.f)); err != nil {
+			return rec, fmt.Errorf("%s: %v", rec.Symbol, err)
+		}
+	}
+	if columns[fTurnoverLacs] >= 0 {
+		lacs, err := parseNumber(get(fTurnoverLacs))
+		if err != nil {
+			return rec, fmt.Errorf("%s: %v", rec.Symbol, err)
+		}
+		rec.Turnover = lacs * 100000
+	}
+	return rec, nil
+}
+
+// readDelivery reads a security wise delivery position file:
+//
+//	Security Wise Delivery Position - Compulsory Rolling Settlement
+//	10,MTO,02012018,592664283,0001111
+//	Trade Date <02-JAN-2018>,Settlement Type <N>,...
+//	Record Type,Sr No,Name of Security,Type,Quantity Traded,Deliverable Quantity,...
+//	20,1,20MICRONS,EQ,47045,27590,58.65
+func readDelivery(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	var date time.Time
+	var records []Record
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(fields) >= 3 && fields[0] == "10":
+			if date, err = time.Parse("02012006", fields[2]); err != nil {
+				return nil, fmt.Errorf("delivery position date: %v", err)
+			}
+		case len(fields) >= 6 && fields[0] == "20":
+			if date.IsZero() {
+				return nil, fmt.Errorf("delivery position without a date")
+			}
+			rec := Record{Symbol: strings.TrimSpace(fields[2]), Series: strings.TrimSpace(fields[3]), Date: date}
+			if rec.Volume, err = parseNumber(fields[4]); err != nil {
+				return nil, err
+			}
+			if rec.Delivery, err = parseNumber(fields[5]); err != nil {
+				return nil, err
+			}
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+// ReadFile reads a bhavcopy file, or every bhavcopy in a zip file. Zip
+// files within it are skipped.
+func ReadFile(file string) ([]Record, error) {
+	if !isZip(file) {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		records, err := Read(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		return records, nil
+	}
+	z, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer z.Close()
+	var records []Record
+	for _, entry := range z.File {
+		if !isBhavcopy(entry.Name) {
+			continue
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+		r, err := Read(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %v", file, entry.Name, err)
+		}
+		records = append(records, r...)
+	}
+	return records, nil
+}
+
+func isBhavcopy(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".dat":
+		return true
+	}
+	return false
+}
+
+func isZip(name string) bool {
+	return strings.ToLower(filepath.Ext(name)) == ".zip"
+}
+
+// ReadDir reads every bhavcopy and delivery file in a folder, zipped or
+// not, keeping the records of the given series
+func ReadDir(dir string, series []string) ([]Record, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	keep := make(map[string]bool, len(series))
+	for _, s := range series {
+		keep[strings.ToUpper(s)] = true
+	}
+	var records []Record
+	for _, file := range files {
+		if file.IsDir() || !isBhavcopy(file.Name()) && !isZip(file.Name()) {
+			continue
+		}
+		r, err := ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, rec := range r {
+			if keep[rec.Series] {
+				records = append(records, rec)
+			}
+		}
+	}
+	return records, nil
+}
+
+// History is the sessions of one symbol in date order
+type History struct {
+	Symbol   string
+	Sessions []Record
+}
+
+// Histories groups the records by symbol, one session per date. Delivery
+// position records fill in the deliverable quantity of the session of the
+// same symbol, series and date; a symbol traded in two series on one day,
+// such as after a move from EQ to BE, keeps the session with more volume.
+func Histories(records []Record) map[string]*History {
+	type key struct {
+		symbol string
+		date   time.Time
+	}
+	type seriesKey struct {
+		key
+		series string
+	}
+	sessions := make(map[key]Record)
+	delivery := make(map[seriesKey]float64)
+	for _, rec := range records {
+		k := key{rec.Symbol, rec.Date}
+		if rec.High == 0 && rec.Close == 0 {
+			if !math.IsNaN(rec.Delivery) {
+				delivery[seriesKey{k, rec.Series}] = rec.Delivery
+			}
+			continue
+		}
+		if have, ok := sessions[k]; ok && have.Volume >= rec.Volume {
+			continue
+		}
+		sessions[k] = rec
+	}
+	histories := make(map[string]*History)
+	for k, rec := range sessions {
+		if d, ok := delivery[seriesKey{k, rec.Series}]; ok && math.IsNaN(rec.Delivery) {
+			rec.Delivery = d
+		}
+		h, ok := histories[k.symbol]
+		if !ok {
+			h = &History{Symbol: k.symbol}
+			histories[k.symbol] = h
+		}
+		h.Sessions = append(h.Sessions, rec)
+	}
+	for _, h := range histories {
+		sort.Slice(h.Sessions, func(i, j int) bool { return h.Sessions[i].Date.Before(h.Sessions[j].Date) })
+	}
+	return histories
+}
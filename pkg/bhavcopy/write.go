@@ -0,0 +1,134 @@
+package bhavcopy
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"pkg/quotes"
+	"strconv"
+	"time"
+)
+
+// QuoteData returns the history as quotes with their turnover and, when
+// the bhavcopies had it, deliverable quantity. Bhavcopy prices are not
+// adjusted, so AdjClose is the close.
+func (h *History) QuoteData() *quotes.QuoteData {
+	n := len(h.Sessions)
+	qd := &quotes.QuoteData{
+		Symbol:    h.Symbol,
+		Dates:     make([]time.Time, n),
+		Opens:     make([]float64, n),
+		Highs:     make([]float64, n),
+		Lows:      make([]float64, n),
+		Closes:    make([]float64, n),
+		AdjCloses: make([]float64, n),
+		Volumes:   make([]float64, n),
+		Turnovers: make([]float64, n),
+	}
+	var deliveries []float64
+	for i, rec := range h.Sessions {
+		qd.Dates[i] = rec.Date
+		qd.Opens[i] = rec.Open
+		qd.Highs[i] = rec.High
+		qd.Lows[i] = rec.Low
+		qd.Closes[i] = rec.Close
+		qd.AdjCloses[i] = rec.Close
+		qd.Volumes[i] = rec.Volume
+		if !math.IsNaN(rec.Turnover) {
+			qd.Turnovers[i] = rec.Turnover
+		}
+		if !math.IsNaN(rec.Delivery) {
+			if deliveries == nil {
+				deliveries = make([]float64, n)
+			}
+			deliveries[i] = rec.Delivery
+		}
+	}
+	if deliveries != nil {
+		qd.Deliveries = deliveries
+		qd.DeliveryPcts = make([]float64, n)
+		for i, d := range deliveries {
+			if qd.Volumes[i] > 0 {
+				qd.DeliveryPcts[i] = d / qd.Volumes[i] * 100
+			}
+		}
+	}
+	return qd
+}
+
+// Header is the header of the files written by Write: the columns of the
+// files in data/ followed by the turnover and the deliverable quantity
+var Header = []string{"Date", "Open", "High", "Low", "Close", "Adj Close", "Volume", "Turnover", "Deliverable Qty"}
+
+// Write writes the history in the format of the files in data/, with the
+// turnover and deliverable quantity as extra columns. An unknown
+// deliverable quantity is written as null.
+func (h *History) Write(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+	if err := writer.Write(Header); err != nil {
+		return err
+	}
+	price := func(v float64) string { return strconv.FormatFloat(v, 'f', 6, 64) }
+	number := func(v float64) string {
+		if math.IsNaN(v) {
+			return "null"
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	for _, rec := range h.Sessions {
+		record := []string{rec.Date.Format("02-01-2006"), price(rec.Open), price(rec.High), price(rec.Low),
+			price(rec.Close), price(rec.Close), number(rec.Volume), number(rec.Turnover), number(rec.Delivery)}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteFile merges the history into a quote file, which keeps its format,
+// its sessions and, on the days the history agrees with, its adjusted
+// close; the turnover and deliverable quantity columns are added when the
+// file lacks them. A missing file is written in the format of Write. Either way the
+// file is replaced only once the new one is complete.
+func (h *History) WriteFile(file string) (*quotes.MergeReport, error) {
+	tmp, err := h.writeTemp(file)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		report := &quotes.MergeReport{Symbol: h.Symbol, Added: len(h.Sessions), Policy: quotes.PreferExisting}
+		return report, os.Rename(tmp, file)
+	}
+	defer os.Remove(tmp)
+	return quotes.MergeFile(file, tmp, h.Symbol, quotes.PreferExisting)
+}
+
+// writeTemp writes the history to a temporary file next to the file
+func (h *History) writeTemp(file string) (string, error) {
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return "", err
+	}
+	w := bufio.NewWriter(tmp)
+	err = tmp.Chmod(0644)
+	if err == nil {
+		err = h.Write(w)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
@@ -0,0 +1,89 @@
+package bhavcopy
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"pkg/quotes"
+	"testing"
+	"time"
+)
+
+func TestWriteFileMerges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bhavcopy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "TEST.csv")
+	yahoo := "Date,Open,High,Low,Close,Adj Close,Volume\n" +
+		"01-01-2018,100.000000,120.000000,88.000000,110.000000,99.000000,100000\n" +
+		"02-01-2018,109.000000,121.000000,100.000000,111.000000,100.000000,200000\n"
+	if err := ioutil.WriteFile(file, []byte(yahoo), 0644); err != nil {
+		t.Fatal(err)
+	}
+	day := func(d int) time.Time { return time.Date(2018, 1, d, 0, 0, 0, 0, time.UTC) }
+	h := &History{Symbol: "TEST", Sessions: []Record{
+		{Symbol: "TEST", Date: day(2), Open: 109, High: 121, Low: 100, Close: 111, Volume: 200000, Turnover: 2.2e7, Delivery: math.NaN()},
+		{Symbol: "TEST", Date: day(3), Open: 111, High: 115, Low: 110, Close: 114, Volume: 150000, Turnover: 1.7e7, Delivery: 9e4},
+	}}
+	report, err := h.WriteFile(file)
+	if err != nil || report.Existing != 2 || report.Added != 1 || report.Same != 1 {
+		t.Fatalf("report %v, %v", report, err)
+	}
+	qd, _, err := quotes.LoadFileWithSchema(file, "TEST", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qd.Dates) != 3 || qd.AdjCloses[0] != 99 || qd.AdjCloses[1] != 100 || qd.Closes[2] != 114 {
+		t.Errorf("merged closes %v adjusted %v", qd.Closes, qd.AdjCloses)
+	}
+	// the file had no turnover or delivery columns
+	if !qd.Has(quotes.Turnover) || !qd.Has(quotes.Delivery) || qd.Turnovers[1] != 2.2e7 || qd.Turnovers[2] != 1.7e7 || qd.Deliveries[2] != 9e4 {
+		t.Errorf("merged turnover %v delivery %v", qd.Turnovers, qd.Deliveries)
+	}
+
+	fresh := filepath.Join(dir, "NEW.csv")
+	if report, err := h.WriteFile(fresh); err != nil || report.Added != 2 {
+		t.Fatalf("new file: %v, %v", report, err)
+	}
+	if qd, _, err := quotes.LoadFileWithSchema(fresh, "NEW", nil); err != nil || len(qd.Dates) != 2 || qd.Turnovers[1] != 1.7e7 {
+		t.Errorf("new file %v, %v", qd, err)
+	}
+}
+
+func TestReadZipSkipsArchives(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bhavcopy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "cm01JAN2018bhav.csv.zip")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := zip.NewWriter(f)
+	entries := []struct{ name, data string }{
+		{"cm01JAN2018bhav.csv", "SYMBOL,SERIES,OPEN,HIGH,LOW,CLOSE,LAST,PREVCLOSE,TOTTRDQTY,TOTTRDVAL,TIMESTAMP\n" +
+			"TEST,EQ,100,120,88,110,110,100,100000,11000000,01-JAN-2018\n"},
+		{"older.zip", "PK\x03\x04 not a bhavcopy"},
+	}
+	for _, e := range entries {
+		w, err := z.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(e.data))
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	records, err := ReadFile(file)
+	if err != nil || len(records) != 1 || records[0].Symbol != "TEST" {
+		t.Errorf("records %v, %v", records, err)
+	}
+}
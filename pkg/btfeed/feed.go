@@ -0,0 +1,93 @@
+// Package btfeed feeds quotes loaded by pkg/quotes into gobacktest
+package btfeed
+
+import (
+	"fmt"
+	gbt "github.com/dirkolbrich/gobacktest"
+	"log"
+	"pkg/quotes"
+	"strings"
+)
+
+// Feed is a gobacktest DataHandler streaming one bar per symbol and session
+type Feed struct {
+	gbt.Data
+	Pipeline *quotes.Pipeline
+	Quotes   map[string]*quotes.QuoteData // the quotes streamed, by symbol
+}
+
+// New returns a feed loading symbols through the pipeline
+func New(pipeline *quotes.Pipeline) *Feed {
+	return &Feed{Pipeline: pipeline}
+}
+
+// Load loads the symbols through the pipeline and streams their bars in date order
+func (f *Feed) Load(symbols []string) error {
+	if f.Pipeline == nil {
+		return fmt.Errorf("btfeed: no pipeline to load %s", strings.Join(symbols, ","))
+	}
+	for _, symbol := range symbols {
+		qd, report, err := f.Pipeline.Load(symbol)
+		if err != nil {
+			return fmt.Errorf("%s: %v", symbol, err)
+		}
+		if !report.Clean() {
+			log.Print(report)
+		}
+		f.Add(qd)
+	}
+	return nil
+}
+
+// Add streams the bars of quotes already loaded
+func (f *Feed) Add(data ...*quotes.QuoteData) {
+	stream := f.Stream()
+	for _, qd := range data {
+		f.keep(qd)
+		for i := range qd.Dates {
+			stream = append(stream, bar(qd.Symbol, qd.Quote(i)))
+		}
+	}
+	f.SetStream(stream)
+	f.SortStream()
+}
+
+// AddPanel streams the bars of a panel. Sessions a symbol is missing are
+// left out, forward filled ones too.
+func (f *Feed) AddPanel(p *quotes.Panel) {
+	stream := f.Stream()
+	for _, symbol := range p.Symbols {
+		qd := p.Column(symbol)
+		f.keep(qd)
+		for i := range qd.Dates {
+			if p.Missing(symbol, i) {
+				continue
+			}
+			stream = append(stream, bar(symbol, qd.Quote(i)))
+		}
+	}
+	f.SetStream(stream)
+	f.SortStream()
+}
+
+func (f *Feed) keep(qd *quotes.QuoteData) {
+	if f.Quotes == nil {
+		f.Quotes = make(map[string]*quotes.QuoteData)
+	}
+	f.Quotes[qd.Symbol] = qd
+}
+
+func bar(symbol string, q quotes.Quote) *gbt.Bar {
+	event := gbt.Event{}
+	event.SetTime(q.Date)
+	event.SetSymbol(symbol)
+	return &gbt.Bar{
+		Event:    event,
+		Open:     q.Open,
+		High:     q.High,
+		Low:      q.Low,
+		Close:    q.Close,
+		AdjClose: q.AdjClose,
+		Volume:   int64(q.Volume),
+	}
+}
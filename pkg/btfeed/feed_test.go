@@ -0,0 +1,116 @@
+package btfeed
+
+import (
+	gbt "github.com/dirkolbrich/gobacktest"
+	"math"
+	"pkg/quotes"
+	"pkg/universe"
+	"testing"
+	"time"
+)
+
+func day(d int) time.Time {
+	return time.Date(2018, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+// generated returns n sessions of the symbol from the first of January 2018
+func generated(symbol string, n int, price float64) *quotes.QuoteData {
+	g := &quotes.Generator{Symbol: symbol, Start: day(1), Seed: 1, Price: price, Volume: 1000}
+	return g.Generate(quotes.GBM{Volatility: 0.2}, n)
+}
+
+// strategyOn returns a strategy handling the bar of the symbol's session i
+func strategyOn(qd *quotes.QuoteData, i int) *gbt.Strategy {
+	s := gbt.NewStrategy("test")
+	s.SetEvent(bar(qd.Symbol, qd.Quote(i)))
+	return s
+}
+
+// run runs the algo on the bar of the symbol's session i
+func run(t *testing.T, algo gbt.AlgoHandler, qd *quotes.QuoteData, i int) bool {
+	ok, err := algo.Run(strategyOn(qd, i))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+
+func TestFeed(t *testing.T) {
+	a, b := generated("A", 10, 100), generated("B", 6, 10)
+	f := New(nil)
+	f.Add(a, b)
+	if f.Quotes["A"] != a || f.Quotes["B"] != b {
+		t.Fatal("quotes not kept by symbol")
+	}
+	n := map[string]int{}
+	var last time.Time
+	for event, ok := f.Next(); ok; event, ok = f.Next() {
+		bar := event.(*gbt.Bar)
+		qd := f.Quotes[bar.Symbol()]
+		i := n[bar.Symbol()]
+		if !bar.Time().Equal(qd.Dates[i]) || bar.Close != qd.Closes[i] || bar.Volume != int64(qd.Volumes[i]) {
+			t.Errorf("%s bar %d: %v, want %v", bar.Symbol(), i, bar, qd.Quote(i))
+		}
+		if bar.Time().Before(last) {
+			t.Errorf("%s bar %d at %v after %v", bar.Symbol(), i, bar.Time(), last)
+		}
+		last = bar.Time()
+		n[bar.Symbol()]++
+	}
+	if n["A"] != 10 || n["B"] != 6 {
+		t.Errorf("streamed %v", n)
+	}
+	if err := New(nil).Load([]string{"A"}); err == nil {
+		t.Error("loaded without a pipeline")
+	}
+}
+
+func TestMemberAndTradable(t *testing.T) {
+	a, b := generated("A", 10, 100), generated("B", 10, 10)
+	f := New(nil)
+	f.Add(a, b)
+
+	u := universe.New("TEST", nil)
+	u.Include("A", universe.Period{From: a.Dates[5]})
+	member, notMember := Member(u), NotMember(u)
+	if run(t, member, a, 4) || !run(t, member, a, 5) || run(t, member, b, 5) {
+		t.Error("member algo")
+	}
+	if !run(t, notMember, a, 4) || run(t, notMember, a, 5) || !run(t, notMember, b, 5) {
+		t.Error("not member algo")
+	}
+
+	tradable := Tradable(f, &universe.Filter{MinPrice: 20})
+	if !run(t, tradable, a, 3) || run(t, tradable, b, 3) {
+		t.Error("tradable algo")
+	}
+	if run(t, tradable, generated("C", 5, 100), 3) {
+		t.Error("a symbol the feed does not stream is tradable")
+	}
+}
+
+func TestStreamCatchUp(t *testing.T) {
+	a := generated("A", 10, 100)
+	f := New(nil)
+	f.Add(a)
+	sma := SMA(f, 3)
+	mean := func(i int) float64 { return (a.Closes[i-2] + a.Closes[i-1] + a.Closes[i]) / 3 }
+	if run(t, sma, a, 1) {
+		t.Error("sma before its warm-up")
+	}
+	// the strategy skipped sessions 2 to 5
+	if !run(t, sma, a, 6) || !near(sma.Value(), mean(6)) {
+		t.Errorf("sma %v, want %v", sma.Value(), mean(6))
+	}
+	// going back restarts the indicator
+	if !run(t, sma, a, 3) || !near(sma.Value(), mean(3)) {
+		t.Errorf("sma %v, want %v", sma.Value(), mean(3))
+	}
+	if _, err := SMA(f, 0).Run(strategyOn(a, 3)); err == nil {
+		t.Error("sma of 0 sessions")
+	}
+}
+
+func near(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
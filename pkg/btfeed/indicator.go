@@ -0,0 +1,116 @@
+package btfeed
+
+import (
+	"fmt"
+	gbt "github.com/dirkolbrich/gobacktest"
+	"pkg/quotes"
+	"pkg/talib"
+)
+
+// step feeds session i of the quotes to an indicator and returns its value
+type step func(qd *quotes.QuoteData, i int) float64
+
+// streamAlgo keeps a streaming indicator per symbol over the quotes of the
+// feed. Each run catches the indicator up to the session of the event, so
+// it costs O(1) per bar however seldom the strategy runs it.
+type streamAlgo struct {
+	gbt.Algo
+	feed    *Feed
+	name    string
+	warmup  int // sessions before the first value
+	newStep func() (step, error)
+	streams map[string]*streamState
+	value   float64
+}
+
+type streamState struct {
+	step  step
+	next  int // session to feed next
+	value float64
+}
+
+func newStreamAlgo(f *Feed, name string, warmup int, newStep func() (step, error)) *streamAlgo {
+	return &streamAlgo{feed: f, name: name, warmup: warmup, newStep: newStep, streams: make(map[string]*streamState)}
+}
+
+// SMA is algo.SMA on streaming indicators: the simple moving average of the
+// closes
+func SMA(f *Feed, period int) gbt.AlgoHandler {
+	return newStreamAlgo(f, fmt.Sprintf("SMA%d", period), period-1, func() (step, error) {
+		sma, err := talib.NewSmaStream(period)
+		if err != nil {
+			return nil, err
+		}
+		return func(qd *quotes.QuoteData, i int) float64 {
+			return sma.Next(qd.Closes[i])
+		}, nil
+	})
+}
+
+// EMA is algo.EMA on streaming indicators: the exponential moving average of
+// the closes
+func EMA(f *Feed, period int) gbt.AlgoHandler {
+	return newStreamAlgo(f, fmt.Sprintf("EMA%d", period), period-1, func() (step, error) {
+		ema, err := talib.NewEmaStream(period)
+		if err != nil {
+			return nil, err
+		}
+		return func(qd *quotes.QuoteData, i int) float64 {
+			return ema.Next(qd.Closes[i])
+		}, nil
+	})
+}
+
+// Mfi is algo.Mfi on streaming indicators: the money flow index of the
+// sessions. Unlike algo.Mfi it passes whatever the value, so that it can be
+// compared, and weighs the flows by volume.
+func Mfi(f *Feed, period int) gbt.AlgoHandler {
+	return newStreamAlgo(f, fmt.Sprintf("mfi%d", period), period, func() (step, error) {
+		mfi, err := talib.NewMfiStream(period)
+		if err != nil {
+			return nil, err
+		}
+		return func(qd *quotes.QuoteData, i int) float64 {
+			return mfi.Next(qd.Highs[i], qd.Lows[i], qd.Closes[i], qd.Volumes[i])
+		}, nil
+	})
+}
+
+// Run runs the algo, false until the indicator has warmed up
+func (a *streamAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	event, ok := s.Event()
+	if !ok {
+		return false, nil
+	}
+	qd, ok := a.feed.Quotes[event.Symbol()]
+	if !ok {
+		return false, fmt.Errorf("%s: no quotes for %s", a.name, event.Symbol())
+	}
+	i := qd.Index(event.Time(), quotes.Exact)
+	if i < 0 {
+		return false, nil
+	}
+	st, ok := a.streams[event.Symbol()]
+	if !ok || st.next > i+1 {
+		step, err := a.newStep()
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", a.name, err)
+		}
+		st = &streamState{step: step}
+		a.streams[event.Symbol()] = st
+	}
+	for ; st.next <= i; st.next++ {
+		st.value = st.step(qd, st.next)
+	}
+	a.value = st.value
+	if i < a.warmup {
+		return false, nil
+	}
+	event.Add(a.name, a.value)
+	return true, nil
+}
+
+// Value returns the value of this Algo.
+func (a *streamAlgo) Value() float64 {
+	return a.value
+}
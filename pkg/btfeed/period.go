@@ -0,0 +1,55 @@
+package btfeed
+
+import (
+	gbt "github.com/dirkolbrich/gobacktest"
+	"pkg/calendar"
+	"time"
+)
+
+// periodAlgo passes once a period for each symbol, on its first session
+// from the first trading day of the period
+type periodAlgo struct {
+	gbt.Algo
+	first func(time.Time) time.Time // first trading day of the period of a day
+	last  map[string]time.Time      // period last run, by symbol
+}
+
+func newPeriodAlgo(first func(time.Time) time.Time) *periodAlgo {
+	return &periodAlgo{first: first, last: make(map[string]time.Time)}
+}
+
+// RunWeekly is algo.RunWeekly on the exchange calendar: it passes on the
+// first session of each week, such as the Tuesday after a Monday holiday,
+// rather than on the bar after a change of week
+func RunWeekly(c *calendar.Calendar) gbt.AlgoHandler {
+	return newPeriodAlgo(c.FirstSessionOfWeek)
+}
+
+// RunMonthly is algo.RunMonthly on the exchange calendar: it passes on the
+// first session of each month
+func RunMonthly(c *calendar.Calendar) gbt.AlgoHandler {
+	return newPeriodAlgo(c.FirstSessionOfMonth)
+}
+
+// RunQuarterly is algo.RunQuarterly on the exchange calendar: it passes on
+// the first session of each quarter
+func RunQuarterly(c *calendar.Calendar) gbt.AlgoHandler {
+	return newPeriodAlgo(c.FirstSessionOfQuarter)
+}
+
+// Run runs the algo. A symbol without a bar on the first session of the
+// period passes on its next bar of the period.
+func (a *periodAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	event, ok := s.Event()
+	if !ok {
+		return false, nil
+	}
+	y, m, d := event.Time().Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	first := a.first(day)
+	if first.IsZero() || day.Before(first) || a.last[event.Symbol()].Equal(first) {
+		return false, nil
+	}
+	a.last[event.Symbol()] = first
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package btfeed
+
+import (
+	gbt "github.com/dirkolbrich/gobacktest"
+	"pkg/calendar"
+	"pkg/quotes"
+	"testing"
+	"time"
+)
+
+func TestRunPeriods(t *testing.T) {
+	nse := calendar.New("NSE")
+	nse.AddHoliday(day(1), "New Year")                                    // a Monday
+	nse.AddHoliday(time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC), "Budget") // a Thursday
+	g := &quotes.Generator{Symbol: "A", Start: day(1), Seed: 1, Price: 100, Calendar: nse}
+	a := g.Generate(quotes.GBM{Volatility: 0.2}, 70) // into April
+	for _, c := range []struct {
+		name string
+		algo gbt.AlgoHandler
+		want []time.Time
+	}{
+		// no bar on Monday the 15th: the week runs on the Tuesday
+		{"weekly", RunWeekly(nse), []time.Time{day(2), day(8), day(16), day(22), day(29)}},
+		{"monthly", RunMonthly(nse), []time.Time{day(2), time.Date(2018, 2, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)}},
+		{"quarterly", RunQuarterly(nse), []time.Time{day(2), time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC)}},
+	} {
+		var got []time.Time
+		for i, date := range a.Dates {
+			if date.Equal(day(15)) {
+				continue
+			}
+			if run(t, c.algo, a, i) {
+				got = append(got, date)
+			}
+		}
+		if len(got) < len(c.want) {
+			t.Fatalf("%s ran on %v, want %v", c.name, got, c.want)
+		}
+		for i := range c.want {
+			if !got[i].Equal(c.want[i]) {
+				t.Errorf("%s run %d on %v, want %v", c.name, i, got[i], c.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package btfeed
+
+import (
+	gbt "github.com/dirkolbrich/gobacktest"
+	"log"
+	"pkg/quotes"
+	"pkg/universe"
+)
+
+type memberAlgo struct {
+	gbt.Algo
+	universe *universe.Universe
+	member   bool
+}
+
+// Member passes while the symbol of the event is in the universe on the
+// day of the event, so that strategies only enter symbols they could have
+// picked at the time
+func Member(u *universe.Universe) gbt.AlgoHandler {
+	return &memberAlgo{universe: u, member: true}
+}
+
+// NotMember passes while the symbol of the event is out of the universe,
+// such as after it was dropped from an index
+func NotMember(u *universe.Universe) gbt.AlgoHandler {
+	return &memberAlgo{universe: u, member: false}
+}
+
+// Run runs the algo, returns the bool value of the algo
+func (algo memberAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	event, ok := s.Event()
+	if !ok {
+		return false, nil
+	}
+	return algo.universe.Member(event.Symbol(), event.Time()) == algo.member, nil
+}
+
+type tradableAlgo struct {
+	gbt.Algo
+	feed   *Feed
+	filter *universe.Filter
+	last   map[string]string // reasons last logged, by symbol
+}
+
+// Tradable passes while the symbol of the event meets the liquidity and
+// price limits of the filter on the quotes the feed streams. Each time the
+// reasons a symbol is excluded change they are logged.
+func Tradable(f *Feed, filter *universe.Filter) gbt.AlgoHandler {
+	return &tradableAlgo{feed: f, filter: filter, last: make(map[string]string)}
+}
+
+// Run runs the algo, returns the bool value of the algo
+func (algo tradableAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	event, ok := s.Event()
+	if !ok {
+		return false, nil
+	}
+	qd, ok := algo.feed.Quotes[event.Symbol()]
+	if !ok {
+		return false, nil
+	}
+	reasons := universe.Explain(algo.filter.Exclusions(qd, qd.Index(event.Time(), quotes.Exact)))
+	if reasons != algo.last[event.Symbol()] {
+		if reasons != "" {
+			log.Printf("%s excluded from %s: %s", event.Symbol(), event.Time().Format("2006-01-02"), reasons)
+		}
+		algo.last[event.Symbol()] = reasons
+	}
+	return reasons == "", nil
+}
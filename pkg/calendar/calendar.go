@@ -0,0 +1,228 @@
+// Package calendar knows which days an exchange holds trading sessions
+package calendar
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Calendar holds the weekends and holidays of an exchange. It knows the
+// holidays of the years it covers; in other years only weekends are off.
+type Calendar struct {
+	Name     string
+	holidays map[time.Time]string
+	weekend  [7]bool
+	years    map[int]bool // years covered
+}
+
+// New returns a calendar with Saturday and Sunday off and no holidays
+func New(name string) *Calendar {
+	c := &Calendar{
+		Name:     name,
+		holidays: make(map[time.Time]string),
+		years:    make(map[int]bool),
+	}
+	c.weekend[time.Saturday] = true
+	c.weekend[time.Sunday] = true
+	return c
+}
+
+// LoadHolidays reads an exchange holiday list. Each line holds a date and
+// an optional description; lines starting with # are ignored:
+//
+//	# Date,Holiday
+//	26-01-2018,Republic Day
+//
+// Dates may be written as dd-mm-yyyy or yyyy-mm-dd. The calendar covers
+// every year with a holiday in the list.
+func LoadHolidays(name string, file string) (*Calendar, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c := New(name)
+	reader := csv.NewReader(bufio.NewReader(f))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	for {
+		line, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		lineno, _ := reader.FieldPos(0)
+		date, err := ParseDate(line[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: %v", file, lineno, err)
+		}
+		holiday := ""
+		if len(line) > 1 {
+			holiday = strings.TrimSpace(line[1])
+		}
+		c.AddHoliday(date, holiday)
+	}
+	return c, nil
+}
+
+// Layouts are the date formats of the project's own files: the holiday
+// lists, the symbol master and the universes
+var Layouts = []string{"02-01-2006", "2006-01-02"}
+
+// ParseDate parses a date written in the first of the layouts that fits,
+// Layouts when none are given
+func ParseDate(s string, layouts ...string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = Layouts
+	}
+	s = strings.TrimSpace(s)
+	for _, layout := range layouts {
+		if d, err := time.Parse(layout, s); err == nil {
+			return d, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date %q", s)
+}
+
+// day returns midnight UTC of the calendar day of t
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// AddHoliday closes the exchange on the day and covers its year
+func (c *Calendar) AddHoliday(date time.Time, name string) {
+	c.holidays[day(date)] = name
+	c.years[date.Year()] = true
+}
+
+// Cover marks the year as one whose holidays are all known, for a year
+// without any
+func (c *Calendar) Cover(year int) {
+	c.years[year] = true
+}
+
+// Covers returns true when the holidays of the year of the day are known.
+// Outside those years every weekday is taken as a trading day.
+func (c *Calendar) Covers(date time.Time) bool {
+	return c.years[date.Year()]
+}
+
+// Holiday returns the name of the holiday on the day, if any
+func (c *Calendar) Holiday(date time.Time) (string, bool) {
+	name, ok := c.holidays[day(date)]
+	return name, ok
+}
+
+// IsTradingDay returns true when the exchange holds a session on the day
+func (c *Calendar) IsTradingDay(date time.Time) bool {
+	if c.weekend[date.Weekday()] {
+		return false
+	}
+	_, holiday := c.holidays[day(date)]
+	return !holiday
+}
+
+// NextSession returns the first trading day after the day
+func (c *Calendar) NextSession(date time.Time) time.Time {
+	d := day(date).AddDate(0, 0, 1)
+	for !c.IsTradingDay(d) {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// PrevSession returns the last trading day before the day
+func (c *Calendar) PrevSession(date time.Time) time.Time {
+	d := day(date).AddDate(0, 0, -1)
+	for !c.IsTradingDay(d) {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+// SessionsBetween returns the trading days from one day to another, both included
+func (c *Calendar) SessionsBetween(from time.Time, to time.Time) []time.Time {
+	var sessions []time.Time
+	last := day(to)
+	for d := day(from); !d.After(last); d = d.AddDate(0, 0, 1) {
+		if c.IsTradingDay(d) {
+			sessions = append(sessions, d)
+		}
+	}
+	return sessions
+}
+
+// firstSession returns the first trading day from start up to end, or a zero time
+func (c *Calendar) firstSession(start time.Time, end time.Time) time.Time {
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if c.IsTradingDay(d) {
+			return d
+		}
+	}
+	return time.Time{}
+}
+
+// lastSession returns the last trading day before end down to start, or a zero time
+func (c *Calendar) lastSession(start time.Time, end time.Time) time.Time {
+	for d := end.AddDate(0, 0, -1); !d.Before(start); d = d.AddDate(0, 0, -1) {
+		if c.IsTradingDay(d) {
+			return d
+		}
+	}
+	return time.Time{}
+}
+
+func weekBounds(date time.Time) (time.Time, time.Time) {
+	d := day(date)
+	monday := d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+	return monday, monday.AddDate(0, 0, 7)
+}
+
+func monthBounds(date time.Time) (time.Time, time.Time) {
+	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+func quarterBounds(date time.Time) (time.Time, time.Time) {
+	month := time.Month((int(date.Month())-1)/3*3 + 1)
+	start := time.Date(date.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 3, 0)
+}
+
+// FirstSessionOfWeek returns the first trading day of the Monday to Sunday
+// week of the day, or a zero time when the whole week is closed
+func (c *Calendar) FirstSessionOfWeek(date time.Time) time.Time {
+	return c.firstSession(weekBounds(date))
+}
+
+// LastSessionOfWeek returns the last trading day of the week of the day
+func (c *Calendar) LastSessionOfWeek(date time.Time) time.Time {
+	return c.lastSession(weekBounds(date))
+}
+
+// FirstSessionOfMonth returns the first trading day of the month of the day
+func (c *Calendar) FirstSessionOfMonth(date time.Time) time.Time {
+	return c.firstSession(monthBounds(date))
+}
+
+// LastSessionOfMonth returns the last trading day of the month of the day
+func (c *Calendar) LastSessionOfMonth(date time.Time) time.Time {
+	return c.lastSession(monthBounds(date))
+}
+
+// FirstSessionOfQuarter returns the first trading day of the quarter of the day
+func (c *Calendar) FirstSessionOfQuarter(date time.Time) time.Time {
+	return c.firstSession(quarterBounds(date))
+}
+
+// LastSessionOfQuarter returns the last trading day of the quarter of the day
+func (c *Calendar) LastSessionOfQuarter(date time.Time) time.Time {
+	return c.lastSession(quarterBounds(date))
+}
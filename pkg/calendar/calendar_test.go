@@ -0,0 +1,59 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNSEHolidays(t *testing.T) {
+	nse, err := LoadHolidays("NSE", "../../holidays/NSE.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := 2002; y <= 2018; y++ {
+		if !nse.Covers(date(y, 6, 1)) {
+			t.Errorf("%d not covered", y)
+		}
+	}
+	if nse.Covers(date(2001, 6, 1)) || nse.Covers(date(2019, 6, 1)) {
+		t.Error("covers beyond the list")
+	}
+	// 2008-11-27 closed after the Mumbai attacks, a Thursday
+	if nse.IsTradingDay(date(2008, 11, 27)) || !nse.IsTradingDay(date(2008, 11, 26)) {
+		t.Error("27-11-2008")
+	}
+	// Diwali Balipratipada 2017 on a Friday: the week has four sessions
+	if n := len(nse.SessionsBetween(date(2017, 10, 16), date(2017, 10, 22))); n != 4 {
+		t.Errorf("%d sessions in the week of Diwali 2017", n)
+	}
+	if !nse.NextSession(date(2017, 10, 19)).Equal(date(2017, 10, 23)) || !nse.PrevSession(date(2017, 10, 23)).Equal(date(2017, 10, 19)) {
+		t.Error("sessions around Diwali 2017")
+	}
+	// Holi 2019 is not in the list: a weekday out of the covered years trades
+	if !nse.IsTradingDay(date(2019, 3, 21)) || nse.IsTradingDay(date(2019, 3, 23)) {
+		t.Error("2019 falls back to weekdays")
+	}
+	if !nse.LastSessionOfMonth(date(2018, 3, 1)).Equal(date(2018, 3, 28)) {
+		t.Errorf("last session of March 2018 %v", nse.LastSessionOfMonth(date(2018, 3, 1)))
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	for _, s := range []string{"29-09-2017", "2017-09-29", " 29-09-2017 "} {
+		if d, err := ParseDate(s); err != nil || !d.Equal(date(2017, 9, 29)) {
+			t.Errorf("%q: %v, %v", s, d, err)
+		}
+	}
+	if d, err := ParseDate("29-Sep-2017", "02-Jan-2006"); err != nil || !d.Equal(date(2017, 9, 29)) {
+		t.Errorf("29-Sep-2017: %v, %v", d, err)
+	}
+	for _, s := range []string{"", "31-02-2017", "29/09/2017"} {
+		if _, err := ParseDate(s); err == nil {
+			t.Errorf("%q parsed", s)
+		}
+	}
+}
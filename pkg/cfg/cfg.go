@@ -2,6 +2,12 @@ package cfg
 
 import (
 	"github.com/spf13/viper"
+	"log"
+	"os"
+	"pkg/calendar"
+	"pkg/quotes"
+	"pkg/symbols"
+	"pkg/universe"
 )
 
 type RiskConfiguration interface {
@@ -21,6 +27,12 @@ var rcfg riskCfg = riskCfg{
 	initialCash: -1,
 }
 var dataFolder = ""
+var holidayFile = ""
+var quoteStore = ""
+var benchmark = ""
+var symbolMaster = ""
+var universes = ""
+var filter = universe.Filter{}
 
 func (r *riskCfg) InitialCash() float64 {
 	return r.initialCash
@@ -44,6 +56,13 @@ func GetConfiguration() (*riskCfg, string) {
 	viper.SetDefault("tradeRisk", 0.03)
 	viper.SetDefault("initialCash", 100000)
 	viper.SetDefault("dataFolder", "data")
+	viper.SetDefault("holidays", DefaultHolidayFile)
+	viper.SetDefault("quoteStore", "quotes.db")
+	viper.SetDefault("benchmark", DefaultBenchmark)
+	viper.SetDefault("symbols", "symbols/NSE.csv")
+	viper.SetDefault("universes", "universes")
+	viper.SetDefault("filter.minPrice", 20)
+	viper.SetDefault("filter.window", 20)
 
 	viper.SetConfigType("yaml")
 	viper.SetConfigName("config")
@@ -59,5 +78,116 @@ func GetConfiguration() (*riskCfg, string) {
 		period:    viper.GetInt("period"),
 	}
 	dataFolder = viper.GetString("dataFolder")
+	holidayFile = viper.GetString("holidays")
+	quoteStore = viper.GetString("quoteStore")
+	benchmark = viper.GetString("benchmark")
+	symbolMaster = viper.GetString("symbols")
+	universes = viper.GetString("universes")
+	filter = universe.Filter{
+		MinPrice:          viper.GetFloat64("filter.minPrice"),
+		MinTurnover:       viper.GetFloat64("filter.minTurnover"),
+		MinDeliveryPct:    viper.GetFloat64("filter.minDeliveryPct"),
+		MaxZeroVolumeDays: viper.GetInt("filter.maxZeroVolumeDays"),
+		MinListingDays:    viper.GetInt("filter.minListingDays"),
+		MinRSRank:         viper.GetFloat64("filter.minRSRank"),
+		Window:            viper.GetInt("filter.window"),
+	}
 	return &rcfg, dataFolder
 }
+
+// HolidayFile returns the exchange holiday list used for the trading calendar
+func HolidayFile() string {
+	GetConfiguration()
+	return holidayFile
+}
+
+// QuoteStore returns the SQLite file holding the quote histories
+func QuoteStore() string {
+	GetConfiguration()
+	return quoteStore
+}
+
+// DefaultBenchmark is the symbol stocks are judged against without one in
+// config.yaml: the largest weight of the NIFTY 50 among the quotes kept
+// with the project, as they hold no index
+const DefaultBenchmark = "HDFCBANK"
+
+// Benchmark returns the symbol of the index stocks are judged against; its
+// quotes are in the data folder like those of any other symbol
+func Benchmark() string {
+	GetConfiguration()
+	return benchmark
+}
+
+// SymbolMaster returns the file describing the symbols: exchange, aliases,
+// ISIN, sector, tick and lot size, listing dates
+func SymbolMaster() string {
+	GetConfiguration()
+	return symbolMaster
+}
+
+// HasConfiguration returns true when the current folder holds config.yaml.
+// The commands run from a folder of quote files, such as quoter and trader,
+// check it first as reading the configuration panics without one.
+func HasConfiguration() bool {
+	_, err := os.Stat("config.yaml")
+	return err == nil
+}
+
+// SymbolMasterOrDefault returns the symbol master of config.yaml, or the
+// one kept with the project when the current folder has no configuration
+func SymbolMasterOrDefault() string {
+	if !HasConfiguration() {
+		log.Printf("No config.yaml, reading the symbol master from %s", symbols.DefaultFile)
+		return symbols.DefaultFile
+	}
+	return SymbolMaster()
+}
+
+// DefaultHolidayFile is the NSE holiday list kept with the project
+const DefaultHolidayFile = "holidays/NSE.csv"
+
+// Calendar returns the NSE trading calendar of the holiday list of
+// config.yaml, or of the one kept with the project without a configuration
+func Calendar() (*calendar.Calendar, error) {
+	file := DefaultHolidayFile
+	if HasConfiguration() {
+		file = HolidayFile()
+	}
+	return calendar.LoadHolidays("NSE", file)
+}
+
+// Validator returns the validator the commands check quotes with, whose
+// gaps are judged against Calendar. Gaps are judged against weekdays when
+// the holiday list cannot be read.
+func Validator() *quotes.Validator {
+	validator := &quotes.Validator{}
+	if nse, err := Calendar(); err == nil {
+		validator.Calendar = nse
+	} else {
+		log.Printf("No holiday list, gaps are checked against weekdays: %v", err)
+	}
+	return validator
+}
+
+// Pipeline returns the pipeline the commands load the quotes of a folder
+// with: cached, validated by Validator and resolving names through the
+// symbol master. Callers set the file suffix and the price mode they need.
+func Pipeline(dir string, master *symbols.Master) *quotes.Pipeline {
+	return &quotes.Pipeline{Dir: dir, CacheDir: ".cache", Validator: Validator(), Symbols: master}
+}
+
+// Universes returns the folder of the universe files, one NAME.csv per universe
+func Universes() string {
+	GetConfiguration()
+	return universes
+}
+
+// Filter returns the liquidity and price limits a symbol must meet on a day
+// to be traded. Each call returns a copy the caller may change, such as to
+// set its symbol master.
+func Filter() *universe.Filter {
+	GetConfiguration()
+	f := filter
+	return &f
+}
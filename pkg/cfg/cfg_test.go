@@ -0,0 +1,32 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"pkg/symbols"
+	"testing"
+)
+
+// quoter and trader run from a folder of quote files without config.yaml
+func TestWithoutConfiguration(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if HasConfiguration() {
+		t.Fatal("configuration found in an empty folder")
+	}
+	if file := SymbolMasterOrDefault(); file != symbols.DefaultFile {
+		t.Errorf("symbol master %s, want %s", file, symbols.DefaultFile)
+	}
+	if p := Pipeline("", nil); p.Validator == nil || p.Validator.Calendar != nil {
+		t.Errorf("pipeline validator %v", p.Validator)
+	}
+	if rcfg.initialCash != -1 {
+		t.Error("configuration read")
+	}
+}
+
+// rs runs out of the box against the default benchmark
+func TestDefaultBenchmark(t *testing.T) {
+	if _, err := os.Stat(filepath.Join("..", "..", "data", DefaultBenchmark+".csv")); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,262 @@
+// Package indicator describes the talib indicators, their inputs,
+// parameters, outputs and lookback, so that they can be listed and
+// computed by name, such as from a configuration file
+package indicator
+
+import (
+	"fmt"
+	"math"
+	"pkg/quotes"
+	"pkg/talib"
+	"sort"
+	"strings"
+)
+
+// Input is a series of the quotes an indicator reads
+type Input int
+
+const (
+	Open Input = iota + 1
+	High
+	Low
+	Close
+	Volume
+)
+
+func (i Input) String() string {
+	switch i {
+	case Open:
+		return "open"
+	case High:
+		return "high"
+	case Low:
+		return "low"
+	case Close:
+		return "close"
+	case Volume:
+		return "volume"
+	}
+	return "unknown"
+}
+
+// series returns the values of the input in the quotes
+func (i Input) series(qd *quotes.QuoteData) []float64 {
+	switch i {
+	case Open:
+		return qd.Opens
+	case High:
+		return qd.Highs
+	case Low:
+		return qd.Lows
+	case Close:
+		return qd.Closes
+	case Volume:
+		return qd.Volumes
+	}
+	return nil
+}
+
+// ParamType is the kind of value a parameter takes
+type ParamType int
+
+const (
+	Integer       ParamType = iota + 1
+	Real                    // any number
+	MovingAverage           // a talib.MaType, from 0 for SMA to 8 for T3
+)
+
+func (t ParamType) String() string {
+	switch t {
+	case Integer:
+		return "integer"
+	case Real:
+		return "real"
+	case MovingAverage:
+		return "moving average type"
+	}
+	return "unknown"
+}
+
+// Param describes a parameter of an indicator. Its name is the one TA-Lib
+// gives it, such as timeperiod or nbdevup.
+type Param struct {
+	Name    string
+	Type    ParamType
+	Default float64
+	Min     float64
+	Max     float64
+}
+
+// Params holds parameter values by name
+type Params map[string]float64
+
+// Int returns a parameter as an integer
+func (p Params) Int(name string) int {
+	return int(p[name])
+}
+
+// Indicator describes an indicator and computes it
+type Indicator struct {
+	Name     string // TA-Lib name, such as BBANDS
+	Title    string
+	Group    string // TA-Lib function group, such as Overlap Studies
+	Inputs   []Input
+	Params   []Param
+	Outputs  []string
+	lookback func(p Params) int
+	compute  func(in [][]float64, p Params) [][]float64
+}
+
+// Defaults returns the default parameters of the indicator
+func (ind *Indicator) Defaults() Params {
+	p := make(Params, len(ind.Params))
+	for _, param := range ind.Params {
+		p[param.Name] = param.Default
+	}
+	return p
+}
+
+// Resolve returns the parameters given completed with the defaults, or an
+// error when one is unknown, out of its range or not a whole number where
+// one is expected
+func (ind *Indicator) Resolve(params Params) (Params, error) {
+	p := ind.Defaults()
+	for name, v := range params {
+		name = strings.ToLower(name)
+		if _, ok := p[name]; !ok {
+			return nil, fmt.Errorf("%s has no parameter %s", ind.Name, name)
+		}
+		p[name] = v
+	}
+	for _, param := range ind.Params {
+		v := p[param.Name]
+		if math.IsNaN(v) || v < param.Min || v > param.Max {
+			return nil, fmt.Errorf("%s: %s %v out of range %v to %v", ind.Name, param.Name, v, param.Min, param.Max)
+		}
+		if param.Type != Real && v != math.Trunc(v) {
+			return nil, fmt.Errorf("%s: %s %v is not a whole number", ind.Name, param.Name, v)
+		}
+	}
+	return p, nil
+}
+
+// Lookback returns the number of leading sessions before every output of
+// the indicator is valid for the parameters, or -1 when they are invalid.
+// The outputs are 0 over them, but for a few that the port sets before
+// they settle, such as the bands of BBANDS over a DEMA.
+func (ind *Indicator) Lookback(params Params) int {
+	p, err := ind.Resolve(params)
+	if err != nil {
+		return -1
+	}
+	return ind.lookback(p)
+}
+
+// Compute computes the indicator over the quotes. Parameters left out take
+// their defaults; the outputs are keyed by the names in Outputs.
+func (ind *Indicator) Compute(qd *quotes.QuoteData, params Params) (map[string][]float64, error) {
+	return ind.run(qd, params, false)
+}
+
+// ComputeNaN is Compute with the outputs NaN over the lookback, so that a
+// session without a value cannot be taken for a 0
+func (ind *Indicator) ComputeNaN(qd *quotes.QuoteData, params Params) (map[string][]float64, error) {
+	return ind.run(qd, params, true)
+}
+
+func (ind *Indicator) run(qd *quotes.QuoteData, params Params, nan bool) (map[string][]float64, error) {
+	p, err := ind.Resolve(params)
+	if err != nil {
+		return nil, err
+	}
+	lookback := ind.lookback(p)
+	if len(qd.Dates) <= lookback {
+		return nil, fmt.Errorf("%s: %d sessions, needs more than %d", ind.Name, len(qd.Dates), lookback)
+	}
+	in := make([][]float64, len(ind.Inputs))
+	for k, input := range ind.Inputs {
+		in[k] = input.series(qd)
+	}
+	out := ind.compute(in, p)
+	if nan {
+		talib.WarmUpNaN(lookback, out...)
+	}
+	outputs := make(map[string][]float64, len(out))
+	for k, name := range ind.Outputs {
+		outputs[name] = out[k]
+	}
+	return outputs, nil
+}
+
+var registry = make(map[string]*Indicator)
+
+func register(ind *Indicator) {
+	registry[ind.Name] = ind
+}
+
+// Lookup returns the indicator of a name, such as bbands or BBANDS
+func Lookup(name string) (*Indicator, bool) {
+	ind, ok := registry[strings.ToUpper(name)]
+	return ind, ok
+}
+
+// List returns the indicators by group and name
+func List() []*Indicator {
+	list := make([]*Indicator, 0, len(registry))
+	for _, ind := range registry {
+		list = append(list, ind)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Group != list[j].Group {
+			return list[i].Group < list[j].Group
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Compute computes an indicator by name over the quotes
+func Compute(name string, qd *quotes.QuoteData, params Params) (map[string][]float64, error) {
+	ind, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown indicator %s", name)
+	}
+	return ind.Compute(qd, params)
+}
+
+// Spec is an indicator and its parameters, such as one a strategy reads
+type Spec struct {
+	Name   string
+	Params Params
+}
+
+// ComputeNaN computes the indicator of the spec with its outputs NaN over
+// the lookback
+func (spec Spec) ComputeNaN(qd *quotes.QuoteData) (map[string][]float64, error) {
+	ind, ok := Lookup(spec.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown indicator %s", spec.Name)
+	}
+	return ind.ComputeNaN(qd, spec.Params)
+}
+
+// WarmUp returns the number of leading sessions before every output of all
+// the indicators is valid, so that a strategy reading them can start on the
+// session of that index
+func WarmUp(specs ...Spec) (int, error) {
+	warmUp := 0
+	for _, spec := range specs {
+		ind, ok := Lookup(spec.Name)
+		if !ok {
+			return 0, fmt.Errorf("unknown indicator %s", spec.Name)
+		}
+		p, err := ind.Resolve(spec.Params)
+		if err != nil {
+			return 0, err
+		}
+		if lookback := ind.lookback(p); lookback > warmUp {
+			warmUp = lookback
+		}
+	}
+	return warmUp, nil
+}
@@ -0,0 +1,164 @@
+package indicator
+
+import (
+	"math"
+	"pkg/quotes"
+	"testing"
+	"time"
+)
+
+func testQuotes(n int) *quotes.QuoteData {
+	g := &quotes.Generator{Symbol: "SYNTH", Start: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Seed: 5, Price: 100, Volume: 1e5}
+	return g.Generate(quotes.GBM{Drift: 0.05, Volatility: 0.3}, n)
+}
+
+// alternates are parameters besides the defaults to check lookbacks with
+var alternates = map[string][]Params{
+	"BBANDS":   {{"timeperiod": 20, "matype": 1}, {"timeperiod": 10, "matype": 3}, {"timeperiod": 40, "matype": 7}},
+	"MA":       {{"timeperiod": 1}, {"timeperiod": 10, "matype": 2}, {"timeperiod": 10, "matype": 5}, {"timeperiod": 5, "matype": 8}, {"matype": 6}, {"matype": 7}},
+	"APO":      {{"fastperiod": 30, "slowperiod": 10}, {"matype": 1}},
+	"MACD":     {{"fastperiod": 26, "slowperiod": 12}, {"signalperiod": 2}},
+	"MACDEXT":  {{"fastmatype": 1, "slowmatype": 1, "signalmatype": 1}, {"slowmatype": 3}},
+	"STOCH":    {{"fastk_period": 14, "slowk_matype": 1, "slowd_period": 5}, {"slowk_period": 1, "slowd_period": 1}},
+	"STOCHF":   {{"fastk_period": 14, "fastd_matype": 2}},
+	"STOCHRSI": {{"timeperiod": 5, "fastk_period": 3, "fastd_period": 1}},
+	"ULTOSC":   {{"timeperiod1": 30, "timeperiod2": 5, "timeperiod3": 10}},
+	"ADOSC":    {{"fastperiod": 10, "slowperiod": 3}},
+	"ADX":      {{"timeperiod": 5}},
+	"TRIX":     {{"timeperiod": 5}},
+	"T3":       {{"timeperiod": 3}},
+	"KELTNER":  {{"atrperiod": 30}},
+	"ICHIMOKU": {{"displacement": 0}, {"conversionperiod": 60}},
+}
+
+// zeroes are indicators whose first value may well be 0, besides the
+// candlestick patterns
+var zeroes = map[string]bool{
+	"BOP": true, "HT_TRENDMODE": true, "AROON": true, "AROONOSC": true, "STOCHRSI": true,
+	"MAXINDEX": true, "MININDEX": true, "MINMAXINDEX": true, "CEIL": true, "FLOOR": true,
+}
+
+// early are outputs set before the lookback of their indicator
+var early = map[string]bool{
+	"BBANDS upperband": true, "BBANDS middleband": true, "BBANDS lowerband": true,
+	"MACD macd": true, "MACD macdsignal": true, "MACDFIX macd": true, "MACDFIX macdsignal": true,
+	"MACDEXT macd": true, "MACDEXT macdsignal": true, "MACDEXT macdhist": true,
+	"ICHIMOKU conversion": true, "ICHIMOKU base": true, "ICHIMOKU spana": true, "ICHIMOKU spanb": true,
+	"ICHIMOKU lagging": true,
+}
+
+func TestLookbacks(t *testing.T) {
+	qd := testQuotes(300)
+	for _, ind := range List() {
+		for _, params := range append([]Params{nil}, alternates[ind.Name]...) {
+			lookback := ind.Lookback(params)
+			outputs, err := ind.Compute(qd, params)
+			if err != nil {
+				t.Fatalf("%s %v: %v", ind.Name, params, err)
+			}
+			settled, unset := true, false
+			for _, name := range ind.Outputs {
+				out := outputs[name]
+				if len(out) != len(qd.Dates) {
+					t.Fatalf("%s %v: %s has %d values for %d sessions", ind.Name, params, name, len(out), len(qd.Dates))
+				}
+				if early[ind.Name+" "+name] {
+					unset = true
+				} else {
+					for i := 0; i < lookback; i++ {
+						if out[i] != 0 {
+							t.Errorf("%s %v: lookback %d but %s is %v at %d", ind.Name, params, lookback, name, out[i], i)
+							break
+						}
+					}
+				}
+				settled = settled && out[lookback] != 0
+				unset = unset || lookback == 0 || out[lookback-1] == 0
+			}
+			if zeroes[ind.Name] || ind.Group == patterns {
+				continue
+			}
+			if !settled {
+				t.Errorf("%s %v: no value of every output at lookback %d", ind.Name, params, lookback)
+			}
+			if !unset {
+				t.Errorf("%s %v: every output has a value before lookback %d", ind.Name, params, lookback)
+			}
+		}
+	}
+}
+
+func TestShortQuotes(t *testing.T) {
+	for _, ind := range List() {
+		lookback := ind.Lookback(nil)
+		if _, err := ind.Compute(testQuotes(lookback), nil); err == nil {
+			t.Errorf("%s: no error on %d sessions", ind.Name, lookback)
+		}
+		if _, err := ind.Compute(testQuotes(lookback+1), nil); err != nil {
+			t.Errorf("%s: %v", ind.Name, err)
+		}
+	}
+}
+
+func TestWarmUp(t *testing.T) {
+	warmUp, err := WarmUp(Spec{"SMA", Params{"timeperiod": 50}}, Spec{"aroon", Params{"timeperiod": 20}}, Spec{"MACD", nil})
+	if err != nil || warmUp != 49 {
+		t.Errorf("warm-up %d, %v", warmUp, err)
+	}
+	if _, err := WarmUp(Spec{"SMA", Params{"timeperiod": 0}}); err == nil {
+		t.Error("warm-up of an SMA of 0")
+	}
+	sma, err := Spec{"sma", Params{"timeperiod": 10}}.ComputeNaN(testQuotes(30))
+	if err != nil || !math.IsNaN(sma["real"][8]) || math.IsNaN(sma["real"][9]) {
+		t.Errorf("sma %v, %v", sma["real"], err)
+	}
+	bbands, ok := Lookup("BBANDS")
+	if !ok {
+		t.Fatal("no BBANDS")
+	}
+	bands, err := bbands.ComputeNaN(testQuotes(30), Params{"timeperiod": 10, "matype": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range bbands.Outputs {
+		if !math.IsNaN(bands[name][17]) || math.IsNaN(bands[name][18]) {
+			t.Errorf("%s: %v at 17, %v at 18", name, bands[name][17], bands[name][18])
+		}
+	}
+	// every line holds a value from the lookback to the last session
+	ichimoku, _ := Lookup("ICHIMOKU")
+	qd := testQuotes(120)
+	lines, err := ichimoku.ComputeNaN(qd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range ichimoku.Outputs {
+		for i := ichimoku.Lookback(nil); i < len(qd.Dates); i++ {
+			if v := lines[name][i]; math.IsNaN(v) || v == 0 {
+				t.Fatalf("ichimoku %s: %v at %d", name, v, i)
+			}
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	bbands, ok := Lookup("bbands")
+	if !ok {
+		t.Fatal("no BBANDS")
+	}
+	p, err := bbands.Resolve(Params{"TimePeriod": 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p["timeperiod"] != 20 || p["nbdevup"] != 2 || p["matype"] != 0 {
+		t.Errorf("resolved %v", p)
+	}
+	for _, params := range []Params{{"period": 20}, {"timeperiod": 1}, {"timeperiod": 2.5}, {"matype": 9}} {
+		if _, err := bbands.Resolve(params); err == nil {
+			t.Errorf("%v resolved", params)
+		}
+	}
+	if _, err := Compute("NOPE", testQuotes(10), nil); err == nil {
+		t.Error("unknown indicator computed")
+	}
+}
@@ -0,0 +1,555 @@
+package indicator
+
+import (
+	"fmt"
+	"pkg/talib"
+)
+
+// Function groups, as TA-Lib names them
+const (
+	overlap    = "Overlap Studies"
+	momentum   = "Momentum Indicators"
+	volumes    = "Volume Indicators"
+	volatility = "Volatility Indicators"
+	prices     = "Price Transform"
+	cycle      = "Cycle Indicators"
+	statistic  = "Statistic Functions"
+	transform  = "Math Transform"
+	operator   = "Math Operators"
+	patterns   = "Pattern Recognition"
+)
+
+var (
+	closes = []Input{Close}
+	hl     = []Input{High, Low}
+	hlc    = []Input{High, Low, Close}
+	hlcv   = []Input{High, Low, Close, Volume}
+	ohlc   = []Input{Open, High, Low, Close}
+	output = []string{"real"}
+)
+
+func period(name string, def float64, min float64) Param {
+	return Param{Name: name, Type: Integer, Default: def, Min: min, Max: 100000}
+}
+
+func number(name string, def float64, min float64, max float64) Param {
+	return Param{Name: name, Type: Real, Default: def, Min: min, Max: max}
+}
+
+func maType(name string) Param {
+	return Param{Name: name, Type: MovingAverage, Default: 0, Min: 0, Max: float64(talib.T3MA)}
+}
+
+func timePeriod(def float64, min float64) []Param {
+	return []Param{period("timeperiod", def, min)}
+}
+
+func out(series ...[]float64) [][]float64 {
+	return series
+}
+
+// single registers an indicator of one series and a time period
+func single(name string, title string, group string, def float64, min float64, lookback func(int) int, f func([]float64, int) []float64) {
+	register(&Indicator{Name: name, Title: title, Group: group, Inputs: closes, Params: timePeriod(def, min), Outputs: output,
+		lookback: func(p Params) int { return lookback(p.Int("timeperiod")) },
+		compute:  func(in [][]float64, p Params) [][]float64 { return out(f(in[0], p.Int("timeperiod"))) },
+	})
+}
+
+// bars registers an indicator of highs, lows and closes and a time period
+func bars(name string, title string, group string, def float64, min float64, lookback func(int) int, f func([]float64, []float64, []float64, int) []float64) {
+	register(&Indicator{Name: name, Title: title, Group: group, Inputs: hlc, Params: timePeriod(def, min), Outputs: output,
+		lookback: func(p Params) int { return lookback(p.Int("timeperiod")) },
+		compute:  func(in [][]float64, p Params) [][]float64 { return out(f(in[0], in[1], in[2], p.Int("timeperiod"))) },
+	})
+}
+
+// ranges registers an indicator of highs and lows and a time period
+func ranges(name string, title string, group string, def float64, min float64, lookback func(int) int, f func([]float64, []float64, int) []float64) {
+	register(&Indicator{Name: name, Title: title, Group: group, Inputs: hl, Params: timePeriod(def, min), Outputs: output,
+		lookback: func(p Params) int { return lookback(p.Int("timeperiod")) },
+		compute:  func(in [][]float64, p Params) [][]float64 { return out(f(in[0], in[1], p.Int("timeperiod"))) },
+	})
+}
+
+// plain registers a function of one series without parameters
+func plain(name string, title string, group string, lookback int, f func([]float64) []float64) {
+	register(&Indicator{Name: name, Title: title, Group: group, Inputs: closes, Outputs: output,
+		lookback: func(Params) int { return lookback },
+		compute:  func(in [][]float64, p Params) [][]float64 { return out(f(in[0])) },
+	})
+}
+
+// candle registers a candlestick pattern of TA-Lib name, which reads the
+// thresholds of talib.DefaultCandleSettings
+func candle(name string, title string, f func([]float64, []float64, []float64, []float64) []float64) {
+	register(&Indicator{Name: name, Title: title, Group: patterns, Inputs: ohlc, Outputs: []string{"integer"},
+		lookback: func(Params) int { return talib.DefaultCandleSettings.CandleLookback(name) },
+		compute:  func(in [][]float64, p Params) [][]float64 { return out(f(in[0], in[1], in[2], in[3])) },
+	})
+}
+
+// penetrating registers a candlestick pattern with a penetration
+func penetrating(name string, title string, def float64, f func([]float64, []float64, []float64, []float64, float64) []float64) {
+	register(&Indicator{Name: name, Title: title, Group: patterns, Inputs: ohlc,
+		Params: []Param{number("penetration", def, 0, 3e37)}, Outputs: []string{"integer"},
+		lookback: func(Params) int { return talib.DefaultCandleSettings.CandleLookback(name) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(f(in[0], in[1], in[2], in[3], p["penetration"]))
+		},
+	})
+}
+
+// pivots registers pivot points, whose outputs are the pivot and the
+// levels r1, s1 and on
+func pivots(name string, title string, f func([]float64, []float64, []float64) *talib.Pivots) {
+	levels := len(f(nil, nil, nil).Resistance)
+	outputs := []string{"pivot"}
+	for k := 1; k <= levels; k++ {
+		outputs = append(outputs, fmt.Sprintf("r%d", k), fmt.Sprintf("s%d", k))
+	}
+	register(&Indicator{Name: name, Title: title, Group: overlap, Inputs: hlc, Outputs: outputs,
+		lookback: func(Params) int { return talib.PivotsLookback() },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			pivots := f(in[0], in[1], in[2])
+			series := [][]float64{pivots.Pivot}
+			for k := range pivots.Resistance {
+				series = append(series, pivots.Resistance[k], pivots.Support[k])
+			}
+			return series
+		},
+	})
+}
+
+// pair registers a function of two series, highs and lows as in TA-Lib
+func pair(name string, title string, f func([]float64, []float64) []float64) {
+	register(&Indicator{Name: name, Title: title, Group: operator, Inputs: hl, Outputs: output,
+		lookback: func(Params) int { return 0 },
+		compute:  func(in [][]float64, p Params) [][]float64 { return out(f(in[0], in[1])) },
+	})
+}
+
+func init() {
+	// Overlap Studies
+	register(&Indicator{Name: "BBANDS", Title: "Bollinger Bands", Group: overlap, Inputs: closes,
+		Params:   []Param{period("timeperiod", 5, 2), number("nbdevup", 2, -3e37, 3e37), number("nbdevdn", 2, -3e37, 3e37), maType("matype")},
+		Outputs:  []string{"upperband", "middleband", "lowerband"},
+		lookback: func(p Params) int { return talib.BBandsLookback(p.Int("timeperiod"), talib.MaType(p.Int("matype"))) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.BBands(in[0], p.Int("timeperiod"), p["nbdevup"], p["nbdevdn"], talib.MaType(p.Int("matype"))))
+		},
+	})
+	single("DEMA", "Double Exponential Moving Average", overlap, 30, 2, talib.DemaLookback, talib.Dema)
+	single("EMA", "Exponential Moving Average", overlap, 30, 2, talib.EmaLookback, talib.Ema)
+	plain("HT_TRENDLINE", "Hilbert Transform - Instantaneous Trendline", overlap, talib.HtTrendlineLookback(), talib.HtTrendline)
+	single("KAMA", "Kaufman Adaptive Moving Average", overlap, 30, 2, talib.KamaLookback, talib.Kama)
+	register(&Indicator{Name: "MA", Title: "Moving average", Group: overlap, Inputs: closes,
+		Params: []Param{period("timeperiod", 30, 1), maType("matype")}, Outputs: output,
+		lookback: func(p Params) int { return talib.MaLookback(p.Int("timeperiod"), talib.MaType(p.Int("matype"))) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Ma(in[0], p.Int("timeperiod"), talib.MaType(p.Int("matype"))))
+		},
+	})
+	register(&Indicator{Name: "MAMA", Title: "MESA Adaptive Moving Average", Group: overlap, Inputs: closes,
+		Params: []Param{number("fastlimit", 0.5, 0.01, 0.99), number("slowlimit", 0.05, 0.01, 0.99)}, Outputs: []string{"mama", "fama"},
+		lookback: func(Params) int { return talib.MamaLookback() },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Mama(in[0], p["fastlimit"], p["slowlimit"]))
+		},
+	})
+	single("MIDPOINT", "MidPoint over period", overlap, 14, 2, talib.MidPointLookback, talib.MidPoint)
+	ranges("MIDPRICE", "Midpoint Price over period", overlap, 14, 2, talib.MidPriceLookback, talib.MidPrice)
+	register(&Indicator{Name: "SAR", Title: "Parabolic SAR", Group: overlap, Inputs: hl,
+		Params: []Param{number("acceleration", 0.02, 0, 3e37), number("maximum", 0.2, 0, 3e37)}, Outputs: output,
+		lookback: func(Params) int { return talib.SarLookback() },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Sar(in[0], in[1], p["acceleration"], p["maximum"]))
+		},
+	})
+	register(&Indicator{Name: "SAREXT", Title: "Parabolic SAR - Extended", Group: overlap, Inputs: hl,
+		Params: []Param{number("startvalue", 0, -3e37, 3e37), number("offsetonreverse", 0, 0, 3e37),
+			number("accelerationinitlong", 0.02, 0, 3e37), number("accelerationlong", 0.02, 0, 3e37), number("accelerationmaxlong", 0.2, 0, 3e37),
+			number("accelerationinitshort", 0.02, 0, 3e37), number("accelerationshort", 0.02, 0, 3e37), number("accelerationmaxshort", 0.2, 0, 3e37)},
+		Outputs:  output,
+		lookback: func(Params) int { return talib.SarLookback() },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.SarExt(in[0], in[1], p["startvalue"], p["offsetonreverse"],
+				p["accelerationinitlong"], p["accelerationlong"], p["accelerationmaxlong"],
+				p["accelerationinitshort"], p["accelerationshort"], p["accelerationmaxshort"]))
+		},
+	})
+	single("SMA", "Simple Moving Average", overlap, 30, 2, talib.SmaLookback, talib.Sma)
+	register(&Indicator{Name: "T3", Title: "Triple Exponential Moving Average (T3)", Group: overlap, Inputs: closes,
+		Params: []Param{period("timeperiod", 5, 2), number("vfactor", 0.7, 0, 1)}, Outputs: output,
+		lookback: func(p Params) int { return talib.T3Lookback(p.Int("timeperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.T3(in[0], p.Int("timeperiod"), p["vfactor"]))
+		},
+	})
+	single("TEMA", "Triple Exponential Moving Average", overlap, 30, 2, talib.TemaLookback, talib.Tema)
+	single("TRIMA", "Triangular Moving Average", overlap, 30, 2, talib.TrimaLookback, talib.Trima)
+	single("WMA", "Weighted Moving Average", overlap, 30, 2, talib.WmaLookback, talib.Wma)
+
+	// Overlap Studies beyond TA-Lib
+	register(&Indicator{Name: "SUPERTREND", Title: "Supertrend", Group: overlap, Inputs: hlc,
+		Params: []Param{period("timeperiod", 10, 1), number("multiplier", 3, 0, 3e37)}, Outputs: []string{"supertrend", "direction"},
+		lookback: func(p Params) int { return talib.SupertrendLookback(p.Int("timeperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Supertrend(in[0], in[1], in[2], p.Int("timeperiod"), p["multiplier"]))
+		},
+	})
+	register(&Indicator{Name: "ICHIMOKU", Title: "Ichimoku Kinko Hyo", Group: overlap, Inputs: hlc,
+		Params: []Param{period("conversionperiod", 9, 1), period("baseperiod", 26, 1), period("spanbperiod", 52, 1),
+			period("displacement", 26, 0)},
+		Outputs: []string{"conversion", "base", "spana", "spanb", "lagging"},
+		lookback: func(p Params) int {
+			return talib.IchimokuLookback(p.Int("conversionperiod"), p.Int("baseperiod"), p.Int("spanbperiod"), p.Int("displacement"))
+		},
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Ichimoku(in[0], in[1], in[2], p.Int("conversionperiod"), p.Int("baseperiod"),
+				p.Int("spanbperiod"), p.Int("displacement")))
+		},
+	})
+	register(&Indicator{Name: "KELTNER", Title: "Keltner Channels", Group: overlap, Inputs: hlc,
+		Params:   []Param{period("timeperiod", 20, 2), number("multiplier", 2, 0, 3e37), period("atrperiod", 10, 1)},
+		Outputs:  []string{"upperband", "middleband", "lowerband"},
+		lookback: func(p Params) int { return talib.KeltnerLookback(p.Int("timeperiod"), p.Int("atrperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Keltner(in[0], in[1], in[2], p.Int("timeperiod"), p["multiplier"], p.Int("atrperiod")))
+		},
+	})
+	register(&Indicator{Name: "DONCHIAN", Title: "Donchian Channels", Group: overlap, Inputs: hl,
+		Params: timePeriod(20, 2), Outputs: []string{"upperband", "middleband", "lowerband"},
+		lookback: func(p Params) int { return talib.DonchianLookback(p.Int("timeperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Donchian(in[0], in[1], p.Int("timeperiod")))
+		},
+	})
+	register(&Indicator{Name: "VWAP", Title: "Rolling Volume Weighted Average Price", Group: overlap, Inputs: hlcv,
+		Params: timePeriod(20, 1), Outputs: output,
+		lookback: func(p Params) int { return talib.VwapLookback(p.Int("timeperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Vwap(in[0], in[1], in[2], in[3], p.Int("timeperiod")))
+		},
+	})
+	register(&Indicator{Name: "ANCHOREDVWAP", Title: "Anchored Volume Weighted Average Price", Group: overlap, Inputs: hlcv,
+		Params: []Param{period("anchor", 0, 0)}, Outputs: output,
+		lookback: func(p Params) int { return talib.AnchoredVwapLookback(p.Int("anchor")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.AnchoredVwap(in[0], in[1], in[2], in[3], p.Int("anchor")))
+		},
+	})
+	register(&Indicator{Name: "CHANDELIER", Title: "Chandelier Exit", Group: overlap, Inputs: hlc,
+		Params: []Param{period("timeperiod", 22, 2), number("multiplier", 3, 0, 3e37)}, Outputs: []string{"long", "short"},
+		lookback: func(p Params) int { return talib.ChandelierExitLookback(p.Int("timeperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.ChandelierExit(in[0], in[1], in[2], p.Int("timeperiod"), p["multiplier"]))
+		},
+	})
+	pivots("PIVOTS", "Classic Pivot Points", talib.ClassicPivots)
+	pivots("PIVOTS_FIB", "Fibonacci Pivot Points", talib.FibonacciPivots)
+	pivots("PIVOTS_CAMARILLA", "Camarilla Pivot Points", talib.CamarillaPivots)
+
+	// Momentum Indicators
+	bars("ADX", "Average Directional Movement Index", momentum, 14, 2, talib.AdxLookback, talib.Adx)
+	bars("ADXR", "Average Directional Movement Index Rating", momentum, 14, 2, talib.AdxRLookback, talib.AdxR)
+	for _, osc := range []struct {
+		name, title string
+		f           func([]float64, int, int, talib.MaType) []float64
+		lookback    func(int, int, talib.MaType) int
+	}{{"APO", "Absolute Price Oscillator", talib.Apo, talib.ApoLookback}, {"PPO", "Percentage Price Oscillator", talib.Ppo, talib.PpoLookback}} {
+		f, lookback := osc.f, osc.lookback
+		register(&Indicator{Name: osc.name, Title: osc.title, Group: momentum, Inputs: closes,
+			Params:  []Param{period("fastperiod", 12, 2), period("slowperiod", 26, 2), maType("matype")},
+			Outputs: output,
+			lookback: func(p Params) int {
+				return lookback(p.Int("fastperiod"), p.Int("slowperiod"), talib.MaType(p.Int("matype")))
+			},
+			compute: func(in [][]float64, p Params) [][]float64 {
+				return out(f(in[0], p.Int("fastperiod"), p.Int("slowperiod"), talib.MaType(p.Int("matype"))))
+			},
+		})
+	}
+	register(&Indicator{Name: "AROON", Title: "Aroon", Group: momentum, Inputs: hl,
+		Params: timePeriod(14, 2), Outputs: []string{"aroondown", "aroonup"},
+		lookback: func(p Params) int { return talib.AroonLookback(p.Int("timeperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Aroon(in[0], in[1], p.Int("timeperiod")))
+		},
+	})
+	ranges("AROONOSC", "Aroon Oscillator", momentum, 14, 2, talib.AroonOscLookback, talib.AroonOsc)
+	register(&Indicator{Name: "BOP", Title: "Balance Of Power", Group: momentum, Inputs: ohlc, Outputs: output,
+		lookback: func(Params) int { return 0 },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Bop(in[0], in[1], in[2], in[3]))
+		},
+	})
+	single("CMO", "Chande Momentum Oscillator", momentum, 14, 2, talib.CmoLookback, talib.Cmo)
+	bars("CCI", "Commodity Channel Index", momentum, 14, 2, talib.CciLookback, talib.Cci)
+	bars("DX", "Directional Movement Index", momentum, 14, 2, talib.DxLookback, talib.Dx)
+	register(&Indicator{Name: "MACD", Title: "Moving Average Convergence/Divergence", Group: momentum, Inputs: closes,
+		Params:  []Param{period("fastperiod", 12, 2), period("slowperiod", 26, 2), period("signalperiod", 9, 1)},
+		Outputs: []string{"macd", "macdsignal", "macdhist"},
+		lookback: func(p Params) int {
+			return talib.MacdLookback(p.Int("fastperiod"), p.Int("slowperiod"), p.Int("signalperiod"))
+		},
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Macd(in[0], p.Int("fastperiod"), p.Int("slowperiod"), p.Int("signalperiod")))
+		},
+	})
+	register(&Indicator{Name: "MACDEXT", Title: "MACD with controllable MA type", Group: momentum, Inputs: closes,
+		Params: []Param{period("fastperiod", 12, 2), maType("fastmatype"), period("slowperiod", 26, 2), maType("slowmatype"),
+			period("signalperiod", 9, 1), maType("signalmatype")},
+		Outputs: []string{"macd", "macdsignal", "macdhist"},
+		lookback: func(p Params) int {
+			return talib.MacdExtLookback(p.Int("fastperiod"), talib.MaType(p.Int("fastmatype")), p.Int("slowperiod"),
+				talib.MaType(p.Int("slowmatype")), p.Int("signalperiod"), talib.MaType(p.Int("signalmatype")))
+		},
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.MacdExt(in[0], p.Int("fastperiod"), talib.MaType(p.Int("fastmatype")), p.Int("slowperiod"),
+				talib.MaType(p.Int("slowmatype")), p.Int("signalperiod"), talib.MaType(p.Int("signalmatype"))))
+		},
+	})
+	register(&Indicator{Name: "MACDFIX", Title: "MACD Fix 12/26", Group: momentum, Inputs: closes,
+		Params: []Param{period("signalperiod", 9, 1)}, Outputs: []string{"macd", "macdsignal", "macdhist"},
+		lookback: func(p Params) int { return talib.MacdFixLookback(p.Int("signalperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.MacdFix(in[0], p.Int("signalperiod")))
+		},
+	})
+	bars("MINUS_DI", "Minus Directional Indicator", momentum, 14, 1, talib.MinusDILookback, talib.MinusDI)
+	ranges("MINUS_DM", "Minus Directional Movement", momentum, 14, 1, talib.MinusDMLookback, talib.MinusDM)
+	register(&Indicator{Name: "MFI", Title: "Money Flow Index", Group: momentum, Inputs: hlcv,
+		Params: timePeriod(14, 2), Outputs: output,
+		lookback: func(p Params) int { return talib.MfiLookback(p.Int("timeperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Mfi(in[0], in[1], in[2], in[3], p.Int("timeperiod")))
+		},
+	})
+	single("MOM", "Momentum", momentum, 10, 1, talib.MomLookback, talib.Mom)
+	bars("PLUS_DI", "Plus Directional Indicator", momentum, 14, 1, talib.PlusDILookback, talib.PlusDI)
+	ranges("PLUS_DM", "Plus Directional Movement", momentum, 14, 1, talib.PlusDMLookback, talib.PlusDM)
+	single("ROC", "Rate of change : ((price/prevPrice)-1)*100", momentum, 10, 1, talib.RocLookback, talib.Roc)
+	single("ROCP", "Rate of change Percentage: (price-prevPrice)/prevPrice", momentum, 10, 1, talib.RocLookback, talib.Rocp)
+	single("ROCR", "Rate of change ratio: (price/prevPrice)", momentum, 10, 1, talib.RocLookback, talib.Rocr)
+	single("ROCR100", "Rate of change ratio 100 scale: (price/prevPrice)*100", momentum, 10, 1, talib.RocLookback, talib.Rocr100)
+	single("RSI", "Relative strength index", momentum, 14, 2, talib.RsiLookback, talib.Rsi)
+	register(&Indicator{Name: "STOCH", Title: "Stochastic", Group: momentum, Inputs: hlc,
+		Params: []Param{period("fastk_period", 5, 1), period("slowk_period", 3, 1), maType("slowk_matype"),
+			period("slowd_period", 3, 1), maType("slowd_matype")},
+		Outputs: []string{"slowk", "slowd"},
+		lookback: func(p Params) int {
+			return talib.StochLookback(p.Int("fastk_period"), p.Int("slowk_period"), talib.MaType(p.Int("slowk_matype")),
+				p.Int("slowd_period"), talib.MaType(p.Int("slowd_matype")))
+		},
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Stoch(in[0], in[1], in[2], p.Int("fastk_period"), p.Int("slowk_period"), talib.MaType(p.Int("slowk_matype")),
+				p.Int("slowd_period"), talib.MaType(p.Int("slowd_matype"))))
+		},
+	})
+	register(&Indicator{Name: "STOCHF", Title: "Stochastic Fast", Group: momentum, Inputs: hlc,
+		Params:  []Param{period("fastk_period", 5, 1), period("fastd_period", 3, 1), maType("fastd_matype")},
+		Outputs: []string{"fastk", "fastd"},
+		lookback: func(p Params) int {
+			return talib.StochFLookback(p.Int("fastk_period"), p.Int("fastd_period"), talib.MaType(p.Int("fastd_matype")))
+		},
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.StochF(in[0], in[1], in[2], p.Int("fastk_period"), p.Int("fastd_period"), talib.MaType(p.Int("fastd_matype"))))
+		},
+	})
+	register(&Indicator{Name: "STOCHRSI", Title: "Stochastic Relative Strength Index", Group: momentum, Inputs: closes,
+		Params:  []Param{period("timeperiod", 14, 2), period("fastk_period", 5, 1), period("fastd_period", 3, 1), maType("fastd_matype")},
+		Outputs: []string{"fastk", "fastd"},
+		lookback: func(p Params) int {
+			return talib.StochRsiLookback(p.Int("timeperiod"), p.Int("fastk_period"), p.Int("fastd_period"), talib.MaType(p.Int("fastd_matype")))
+		},
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.StochRsi(in[0], p.Int("timeperiod"), p.Int("fastk_period"), p.Int("fastd_period"), talib.MaType(p.Int("fastd_matype"))))
+		},
+	})
+	single("TRIX", "1-day Rate-Of-Change of a Triple Smooth EMA", momentum, 30, 1, talib.TrixLookback, talib.Trix)
+	register(&Indicator{Name: "ULTOSC", Title: "Ultimate Oscillator", Group: momentum, Inputs: hlc,
+		Params:  []Param{period("timeperiod1", 7, 1), period("timeperiod2", 14, 1), period("timeperiod3", 28, 1)},
+		Outputs: output,
+		lookback: func(p Params) int {
+			return talib.UltOscLookback(p.Int("timeperiod1"), p.Int("timeperiod2"), p.Int("timeperiod3"))
+		},
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.UltOsc(in[0], in[1], in[2], p.Int("timeperiod1"), p.Int("timeperiod2"), p.Int("timeperiod3")))
+		},
+	})
+	bars("WILLR", "Williams' %R", momentum, 14, 2, talib.WillRLookback, talib.WillR)
+
+	// Volume Indicators
+	register(&Indicator{Name: "AD", Title: "Chaikin A/D Line", Group: volumes, Inputs: hlcv, Outputs: output,
+		lookback: func(Params) int { return 0 },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Ad(in[0], in[1], in[2], in[3]))
+		},
+	})
+	register(&Indicator{Name: "ADOSC", Title: "Chaikin A/D Oscillator", Group: volumes, Inputs: hlcv,
+		Params: []Param{period("fastperiod", 3, 2), period("slowperiod", 10, 2)}, Outputs: output,
+		lookback: func(p Params) int { return talib.AdOscLookback(p.Int("fastperiod"), p.Int("slowperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.AdOsc(in[0], in[1], in[2], in[3], p.Int("fastperiod"), p.Int("slowperiod")))
+		},
+	})
+	register(&Indicator{Name: "OBV", Title: "On Balance Volume", Group: volumes, Inputs: []Input{Close, Volume}, Outputs: output,
+		lookback: func(Params) int { return 0 },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.Obv(in[0], in[1]))
+		},
+	})
+
+	// Volatility Indicators
+	bars("ATR", "Average True Range", volatility, 14, 1, talib.AtrLookback, talib.Atr)
+	bars("NATR", "Normalized Average True Range", volatility, 14, 1, talib.NatrLookback, talib.Natr)
+	register(&Indicator{Name: "TRANGE", Title: "True Range", Group: volatility, Inputs: hlc, Outputs: output,
+		lookback: func(Params) int { return talib.TRangeLookback() },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.TRange(in[0], in[1], in[2]))
+		},
+	})
+
+	// Price Transform
+	register(&Indicator{Name: "AVGPRICE", Title: "Average Price (o+h+l+c)/4", Group: prices, Inputs: ohlc, Outputs: output,
+		lookback: func(Params) int { return 0 },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.AvgPrice(in[0], in[1], in[2], in[3]))
+		},
+	})
+	register(&Indicator{Name: "MEDPRICE", Title: "Median Price (h+l)/2", Group: prices, Inputs: hl, Outputs: output,
+		lookback: func(Params) int { return 0 },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.MedPrice(in[0], in[1]))
+		},
+	})
+	for _, e := range []struct {
+		name, title string
+		f           func([]float64, []float64, []float64) []float64
+	}{{"TYPPRICE", "Typical Price (h+l+c)/3", talib.TypPrice}, {"WCLPRICE", "Weighted Close Price", talib.WclPrice},
+		{"HLC3", "Average of high, low and close", talib.Hlc3}} {
+		f := e.f
+		register(&Indicator{Name: e.name, Title: e.title, Group: prices, Inputs: hlc, Outputs: output,
+			lookback: func(Params) int { return 0 },
+			compute: func(in [][]float64, p Params) [][]float64 {
+				return out(f(in[0], in[1], in[2]))
+			},
+		})
+	}
+	register(&Indicator{Name: "HEIKINASHI", Title: "Heikin-Ashi candles", Group: prices, Inputs: []Input{High, Open, Close, Low},
+		Outputs:  []string{"high", "open", "close", "low"},
+		lookback: func(Params) int { return talib.HeikinashiCandlesLookback() },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.HeikinashiCandles(in[0], in[1], in[2], in[3]))
+		},
+	})
+
+	// Cycle Indicators
+	plain("HT_DCPERIOD", "Hilbert Transform - Dominant Cycle Period", cycle, talib.HtDcPeriodLookback(), talib.HtDcPeriod)
+	plain("HT_DCPHASE", "Hilbert Transform - Dominant Cycle Phase", cycle, talib.HtDcPhaseLookback(), talib.HtDcPhase)
+	register(&Indicator{Name: "HT_PHASOR", Title: "Hilbert Transform - Phasor Components", Group: cycle, Inputs: closes,
+		Outputs:  []string{"inphase", "quadrature"},
+		lookback: func(Params) int { return talib.HtPhasorLookback() },
+		compute:  func(in [][]float64, p Params) [][]float64 { return out(talib.HtPhasor(in[0])) },
+	})
+	register(&Indicator{Name: "HT_SINE", Title: "Hilbert Transform - SineWave", Group: cycle, Inputs: closes,
+		Outputs:  []string{"sine", "leadsine"},
+		lookback: func(Params) int { return talib.HtSineLookback() },
+		compute:  func(in [][]float64, p Params) [][]float64 { return out(talib.HtSine(in[0])) },
+	})
+	plain("HT_TRENDMODE", "Hilbert Transform - Trend vs Cycle Mode", cycle, talib.HtTrendModeLookback(), talib.HtTrendMode)
+
+	// Statistic Functions
+	for _, e := range []struct {
+		name, title string
+		def         float64
+		lookback    func(int) int
+		f           func([]float64, []float64, int) []float64
+	}{{"BETA", "Beta", 5, talib.BetaLookback, talib.Beta}, {"CORREL", "Pearson's Correlation Coefficient (r)", 30, talib.CorrelLookback, talib.Correl}} {
+		f, lookback := e.f, e.lookback
+		register(&Indicator{Name: e.name, Title: e.title, Group: statistic, Inputs: hl, Params: timePeriod(e.def, 1), Outputs: output,
+			lookback: func(p Params) int { return lookback(p.Int("timeperiod")) },
+			compute: func(in [][]float64, p Params) [][]float64 {
+				return out(f(in[0], in[1], p.Int("timeperiod")))
+			},
+		})
+	}
+	single("LINEARREG", "Linear Regression", statistic, 14, 2, talib.LinearRegLookback, talib.LinearReg)
+	single("LINEARREG_ANGLE", "Linear Regression Angle", statistic, 14, 2, talib.LinearRegLookback, talib.LinearRegAngle)
+	single("LINEARREG_INTERCEPT", "Linear Regression Intercept", statistic, 14, 2, talib.LinearRegLookback, talib.LinearRegIntercept)
+	single("LINEARREG_SLOPE", "Linear Regression Slope", statistic, 14, 2, talib.LinearRegLookback, talib.LinearRegSlope)
+	register(&Indicator{Name: "STDDEV", Title: "Standard Deviation", Group: statistic, Inputs: closes,
+		Params: []Param{period("timeperiod", 5, 2), number("nbdev", 1, -3e37, 3e37)}, Outputs: output,
+		lookback: func(p Params) int { return talib.StdDevLookback(p.Int("timeperiod")) },
+		compute: func(in [][]float64, p Params) [][]float64 {
+			return out(talib.StdDev(in[0], p.Int("timeperiod"), p["nbdev"]))
+		},
+	})
+	single("TSF", "Time Series Forecast", statistic, 14, 2, talib.LinearRegLookback, talib.Tsf)
+	single("VAR", "Variance", statistic, 5, 1, talib.VarLookback, talib.Var)
+
+	// Math Transform
+	for _, f := range []struct {
+		name, title string
+		f           func([]float64) []float64
+	}{
+		{"ACOS", "Vector Trigonometric ACOS", talib.Acos}, {"ASIN", "Vector Trigonometric ASIN", talib.Asin},
+		{"ATAN", "Vector Trigonometric ATAN", talib.Atan}, {"CEIL", "Vector CEIL", talib.Ceil},
+		{"COS", "Vector Trigonometric COS", talib.Cos}, {"COSH", "Vector Trigonometric COSH", talib.Cosh},
+		{"EXP", "Vector arithmetic EXP", talib.Exp}, {"FLOOR", "Vector FLOOR", talib.Floor},
+		{"LN", "Vector natural log LN", talib.Ln}, {"LOG10", "Vector LOG10", talib.Log10},
+		{"SIN", "Vector Trigonometric SIN", talib.Sin}, {"SINH", "Vector Trigonometric SINH", talib.Sinh},
+		{"SQRT", "Vector SQRT", talib.Sqrt}, {"TAN", "Vector Trigonometric TAN", talib.Tan},
+		{"TANH", "Vector Trigonometric TANH", talib.Tanh},
+	} {
+		plain(f.name, f.title, transform, 0, f.f)
+	}
+
+	// Math Operators
+	pair("ADD", "Vector arithmetic addition", talib.Add)
+	pair("DIV", "Vector arithmetic division", talib.Div)
+	pair("MULT", "Vector arithmetic multiply", talib.Mult)
+	pair("SUB", "Vector arithmetic subtraction", talib.Sub)
+	single("MAX", "Highest value over a period", operator, 30, 2, talib.MaxLookback, talib.Max)
+	single("MAXINDEX", "Index of highest value over a specified period", operator, 30, 2, talib.MaxLookback, talib.MaxIndex)
+	single("MIN", "Lowest value over a period", operator, 30, 2, talib.MaxLookback, talib.Min)
+	single("MININDEX", "Index of lowest value over a specified period", operator, 30, 2, talib.MaxLookback, talib.MinIndex)
+	register(&Indicator{Name: "MINMAX", Title: "Lowest and highest values over a specified period", Group: operator, Inputs: closes,
+		Params: timePeriod(30, 2), Outputs: []string{"min", "max"},
+		lookback: func(p Params) int { return talib.MaxLookback(p.Int("timeperiod")) },
+		compute:  func(in [][]float64, p Params) [][]float64 { return out(talib.MinMax(in[0], p.Int("timeperiod"))) },
+	})
+	register(&Indicator{Name: "MINMAXINDEX", Title: "Indexes of lowest and highest values over a specified period", Group: operator, Inputs: closes,
+		Params: timePeriod(30, 2), Outputs: []string{"minidx", "maxidx"},
+		lookback: func(p Params) int { return talib.MaxLookback(p.Int("timeperiod")) },
+		compute:  func(in [][]float64, p Params) [][]float64 { return out(talib.MinMaxIndex(in[0], p.Int("timeperiod"))) },
+	})
+	single("SUM", "Summation", operator, 30, 2, talib.MaxLookback, talib.Sum)
+
+	// Pattern Recognition
+	candle("CDL3BLACKCROWS", "Three Black Crows", talib.Cdl3BlackCrows)
+	candle("CDL3INSIDE", "Three Inside Up/Down", talib.Cdl3Inside)
+	candle("CDL3OUTSIDE", "Three Outside Up/Down", talib.Cdl3Outside)
+	candle("CDL3WHITESOLDIERS", "Three Advancing White Soldiers", talib.Cdl3WhiteSoldiers)
+	penetrating("CDLDARKCLOUDCOVER", "Dark Cloud Cover", 0.5, talib.CdlDarkCloudCover)
+	candle("CDLDOJI", "Doji", talib.CdlDoji)
+	candle("CDLDOJISTAR", "Doji Star", talib.CdlDojiStar)
+	candle("CDLDRAGONFLYDOJI", "Dragonfly Doji", talib.CdlDragonflyDoji)
+	candle("CDLENGULFING", "Engulfing Pattern", talib.CdlEngulfing)
+	penetrating("CDLEVENINGDOJISTAR", "Evening Doji Star", 0.3, talib.CdlEveningDojiStar)
+	penetrating("CDLEVENINGSTAR", "Evening Star", 0.3, talib.CdlEveningStar)
+	candle("CDLGRAVESTONEDOJI", "Gravestone Doji", talib.CdlGravestoneDoji)
+	candle("CDLHAMMER", "Hammer", talib.CdlHammer)
+	candle("CDLHANGINGMAN", "Hanging Man", talib.CdlHangingMan)
+	candle("CDLHARAMI", "Harami Pattern", talib.CdlHarami)
+	candle("CDLHARAMICROSS", "Harami Cross Pattern", talib.CdlHaramiCross)
+	candle("CDLINVERTEDHAMMER", "Inverted Hammer", talib.CdlInvertedHammer)
+	candle("CDLLONGLEGGEDDOJI", "Long Legged Doji", talib.CdlLongLeggedDoji)
+	candle("CDLMARUBOZU", "Marubozu", talib.CdlMarubozu)
+	penetrating("CDLMORNINGDOJISTAR", "Morning Doji Star", 0.3, talib.CdlMorningDojiStar)
+	penetrating("CDLMORNINGSTAR", "Morning Star", 0.3, talib.CdlMorningStar)
+	candle("CDLPIERCING", "Piercing Pattern", talib.CdlPiercing)
+	candle("CDLSHOOTINGSTAR", "Shooting Star", talib.CdlShootingStar)
+	candle("CDLSPINNINGTOP", "Spinning Top", talib.CdlSpinningTop)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/davecgh/go-spew/spew"
+	"pkg/symbols"
 )
 
 type Portfolio struct {
@@ -11,6 +12,7 @@ type Portfolio struct {
 	Capital    float64
 	Assets     map[string]Asset
 	AssetValue float64
+	Symbols    *symbols.Master // assets are kept under the resolved ticker when set
 }
 
 type Asset struct {
@@ -59,7 +61,16 @@ func (a Asset) AveragePrice() float64 {
 	return total / float64(a.Size())
 }
 
+// ticker returns the ticker assets of the symbol are kept under
+func (p *Portfolio) ticker(symbol string) string {
+	if p.Symbols == nil {
+		return symbol
+	}
+	return p.Symbols.Ticker(symbol)
+}
+
 func (p *Portfolio) Buy(symbol string, buy TradeEvent) {
+	symbol = p.ticker(symbol)
 	var cost float64
 	cost = buy.Price * float64(buy.Size)
 	if e, exists := p.Assets[symbol]; exists {
@@ -78,6 +89,7 @@ func (p *Portfolio) Buy(symbol string, buy TradeEvent) {
 }
 
 func (p *Portfolio) Sell(symbol string, sell TradeEvent) ([]TradeEvent, float64, error) {
+	symbol = p.ticker(symbol)
 	spew.Dump("Portfolio.Sell", symbol, sell, p)
 	if a, ok := p.Assets[symbol]; ok {
 		// add proceedings to capital
@@ -134,6 +146,7 @@ func (p *Portfolio) RecalculateAssetValue() {
 }
 
 func (p *Portfolio) UpdateAssetCurrentValue(symbol string, price float64) error {
+	symbol = p.ticker(symbol)
 	if a, ok := p.Assets[symbol]; ok {
 		// update the current price of the asset
 		a.CurrentPrice = price
@@ -0,0 +1,268 @@
+package quotes
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ActionKind int
+
+const (
+	Split ActionKind = iota + 1
+	Bonus
+	Dividend
+)
+
+// CorporateAction is a split, bonus or cash dividend effective from ExDate
+type CorporateAction struct {
+	ExDate time.Time
+	Kind   ActionKind
+	Ratio  float64 // shares held after the action for every share held before
+	Amount float64 // cash per share, for dividends
+}
+
+type PriceMode int
+
+const (
+	RawPrices PriceMode = iota
+	AdjustedPrices
+)
+
+// ActionFile returns the name of the corporate action file of a symbol
+func ActionFile(dir string, symbol string) string {
+	return filepath.Join(dir, symbol+".actions.csv")
+}
+
+// LoadActions reads a corporate action file. Each line holds the ex-date,
+// the kind of action and its value:
+//
+//	# ExDate,Action,Value
+//	22-09-2016,split,5:1     five shares for one
+//	19-07-2018,bonus,1:1     one bonus share for every share held
+//	01-08-2019,dividend,12.5 cash per share
+//
+// The actions are returned in ex-date order.
+func LoadActions(file string) ([]CorporateAction, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	reader := csv.NewReader(bufio.NewReader(f))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	var actions []CorporateAction
+	for {
+		line, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		lineno, _ := reader.FieldPos(0)
+		if len(line) < 3 {
+			return nil, fmt.Errorf("%s: line %d: expected ExDate,Action,Value", file, lineno)
+		}
+		a, err := parseAction(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: %v", file, lineno, err)
+		}
+		actions = append(actions, a)
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].ExDate.Before(actions[j].ExDate)
+	})
+	return actions, nil
+}
+
+func parseAction(line []string) (CorporateAction, error) {
+	var a CorporateAction
+	var err error
+	if a.ExDate, err = time.Parse("02-01-2006", strings.TrimSpace(line[0])); err != nil {
+		return a, err
+	}
+	value := strings.TrimSpace(line[2])
+	switch strings.ToLower(strings.TrimSpace(line[1])) {
+	case "split":
+		a.Kind = Split
+		var n, d float64
+		if n, d, err = parseRatio(value); err != nil {
+			return a, err
+		}
+		a.Ratio = n / d
+	case "bonus":
+		a.Kind = Bonus
+		var n, d float64
+		if n, d, err = parseRatio(value); err != nil {
+			return a, err
+		}
+		a.Ratio = (n + d) / d
+	case "dividend":
+		a.Kind = Dividend
+		if a.Amount, err = strconv.ParseFloat(value, 64); err != nil {
+			return a, err
+		}
+		if a.Amount < 0 {
+			return a, fmt.Errorf("negative dividend %s", value)
+		}
+	default:
+		return a, fmt.Errorf("unknown action %q", line[1])
+	}
+	return a, nil
+}
+
+// parseRatio parses "a:b", or "a" meaning "a:1"
+func parseRatio(value string) (float64, float64, error) {
+	parts := strings.SplitN(value, ":", 2)
+	n, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	d := 1.0
+	if len(parts) == 2 {
+		if d, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err != nil {
+			return 0, 0, err
+		}
+	}
+	if n <= 0 || d <= 0 {
+		return 0, 0, fmt.Errorf("invalid ratio %s", value)
+	}
+	return n, d, nil
+}
+
+// Prices returns the raw quotes, or quotes back-adjusted with the symbol's
+// action file in actionsDir. Without an action file the adjustment is
+// derived from AdjClose.
+func (q *QuoteData) Prices(mode PriceMode, actionsDir string) (*QuoteData, error) {
+	if mode == RawPrices {
+		return q, nil
+	}
+	actions, err := LoadActions(ActionFile(actionsDir, q.Symbol))
+	if os.IsNotExist(err) {
+		return q.AdjustFromAdjClose(), nil
+	} else if err != nil {
+		return nil, err
+	}
+	return q.Adjust(actions), nil
+}
+
+// Adjust returns the quotes back-adjusted for the corporate actions: prices
+// before each ex-date are scaled down and quantities scaled up so that the
+// series is continuous across splits, bonuses and dividends.
+func (q *QuoteData) Adjust(actions []CorporateAction) *QuoteData {
+	adj := q.Clone()
+	for _, a := range actions {
+		price, volume := 1.0, 1.0
+		switch a.Kind {
+		case Split, Bonus:
+			if a.Ratio <= 0 {
+				continue
+			}
+			price, volume = 1/a.Ratio, a.Ratio
+		case Dividend:
+			i := sort.Search(len(q.Dates), func(i int) bool {
+				return !q.Dates[i].Before(a.ExDate)
+			})
+			if i == 0 || i == len(q.Dates) {
+				continue
+			}
+			prev := q.Closes[i-1]
+			if prev <= a.Amount {
+				continue
+			}
+			price = (prev - a.Amount) / prev
+		}
+		for i, d := range adj.Dates {
+			if !d.Before(a.ExDate) {
+				break
+			}
+			adj.Opens[i] *= price
+			adj.Highs[i] *= price
+			adj.Lows[i] *= price
+			adj.Closes[i] *= price
+			adj.Volumes[i] *= volume
+			if adj.Deliveries != nil {
+				adj.Deliveries[i] *= volume
+			}
+			if adj.OpenInterests != nil {
+				adj.OpenInterests[i] *= volume
+			}
+		}
+	}
+	copy(adj.AdjCloses, adj.Closes)
+	return adj
+}
+
+// AdjustFromAdjClose returns the quotes with prices scaled by AdjClose/Close
+// of each session. Sessions without a usable ratio take the ratio of the
+// previous one. Quantities are scaled up by the splits and bonuses alone,
+// found where the ratio steps by a ratio they are issued at; a step the
+// size of a dividend leaves them as they are.
+func (q *QuoteData) AdjustFromAdjClose() *QuoteData {
+	adj := q.Clone()
+	if len(q.AdjCloses) != len(q.Closes) {
+		return adj
+	}
+	factor := 1.0
+	factors := make([]float64, len(adj.Closes))
+	for i := range adj.Closes {
+		if q.Closes[i] > 0 && q.AdjCloses[i] > 0 {
+			factor = q.AdjCloses[i] / q.Closes[i]
+		}
+		factors[i] = factor
+		adj.Opens[i] *= factor
+		adj.Highs[i] *= factor
+		adj.Lows[i] *= factor
+		adj.Closes[i] *= factor
+	}
+	volume := 1.0
+	for i := len(factors) - 2; i >= 0; i-- {
+		volume *= splitRatio(factors[i+1] / factors[i])
+		adj.Volumes[i] *= volume
+		if adj.Deliveries != nil {
+			adj.Deliveries[i] *= volume
+		}
+		if adj.OpenInterests != nil {
+			adj.OpenInterests[i] *= volume
+		}
+	}
+	copy(adj.AdjCloses, adj.Closes)
+	return adj
+}
+
+// resembledAction returns the split of n shares for one, or the bonus of
+// one share for every n held, that divides prices by about the ratio, or 0
+func resembledAction(ratio float64) (ActionKind, float64) {
+	for n := 2.0; n <= 10; n++ {
+		if math.Abs(ratio/n-1) < 0.05 {
+			return Split, n
+		}
+	}
+	for n := 2.0; n <= 3; n++ {
+		if math.Abs(ratio/((n+1)/n)-1) < 0.02 {
+			return Bonus, n
+		}
+	}
+	return 0, 0
+}
+
+// splitRatio returns the shares held after the split or bonus a ratio of
+// prices resembles for every share held before, or 1
+func splitRatio(ratio float64) float64 {
+	switch kind, n := resembledAction(ratio); kind {
+	case Split:
+		return n
+	case Bonus:
+		return (n + 1) / n
+	}
+	return 1
+}
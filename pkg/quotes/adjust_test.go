@@ -0,0 +1,126 @@
+package quotes
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func near(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// daily returns sessions from the first of January 2018 at the closes, each
+// with a range of 1% around its close and 1000 shares traded
+func daily(closes ...float64) *QuoteData {
+	var qs []Quote
+	for i, c := range closes {
+		qs = append(qs, Quote{Date: time.Date(2018, 1, 1+i, 0, 0, 0, 0, time.UTC),
+			Open: c, High: c * 1.01, Low: c * 0.99, Close: c, AdjClose: c, Volume: 1000})
+	}
+	return convert(qs, "TEST")
+}
+
+func TestLoadActions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "actions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := ActionFile(dir, "TEST")
+	data := "# ExDate,Action,Value\n" +
+		"01-08-2019,dividend,12.5\n" +
+		"22-09-2016,split,5:1\n" +
+		"19-07-2018,Bonus,1:2\n" +
+		"01-01-2017,split,10\n"
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	actions, err := LoadActions(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CorporateAction{
+		{ExDate: time.Date(2016, 9, 22, 0, 0, 0, 0, time.UTC), Kind: Split, Ratio: 5},
+		{ExDate: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), Kind: Split, Ratio: 10},
+		{ExDate: time.Date(2018, 7, 19, 0, 0, 0, 0, time.UTC), Kind: Bonus, Ratio: 1.5},
+		{ExDate: time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC), Kind: Dividend, Amount: 12.5},
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("actions %v", actions)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("action %d: %v, want %v", i, actions[i], want[i])
+		}
+	}
+
+	for _, line := range []string{"22-09-2016,split", "2016-09-22,split,5:1", "22-09-2016,split,0:1",
+		"22-09-2016,merger,1", "22-09-2016,dividend,-1"} {
+		if err := ioutil.WriteFile(file, []byte("# ExDate,Action,Value\n"+line+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadActions(file); err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("%s: %v", line, err)
+		}
+	}
+}
+
+func TestAdjust(t *testing.T) {
+	exDate := time.Date(2018, 1, 3, 0, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		name   string
+		closes []float64
+		action CorporateAction
+		price  float64 // the prices before the ex-date are multiplied by
+		volume float64 // the quantities before the ex-date are multiplied by
+	}{
+		{"split", []float64{100, 100, 20, 20}, CorporateAction{ExDate: exDate, Kind: Split, Ratio: 5}, 0.2, 5},
+		{"bonus", []float64{90, 90, 60, 60}, CorporateAction{ExDate: exDate, Kind: Bonus, Ratio: 1.5}, 1 / 1.5, 1.5},
+		{"dividend", []float64{100, 100, 98, 98}, CorporateAction{ExDate: exDate, Kind: Dividend, Amount: 2}, 0.98, 1},
+		{"dividend above the close", []float64{1, 1, 1, 1}, CorporateAction{ExDate: exDate, Kind: Dividend, Amount: 2}, 1, 1},
+	} {
+		q := daily(c.closes...)
+		q.Deliveries = []float64{500, 500, 500, 500}
+		adj := q.Adjust([]CorporateAction{c.action})
+		for i := range q.Dates {
+			price, volume := 1.0, 1.0
+			if i < 2 {
+				price, volume = c.price, c.volume
+			}
+			if !near(adj.Closes[i], q.Closes[i]*price) || !near(adj.Highs[i], q.Highs[i]*price) ||
+				!near(adj.AdjCloses[i], adj.Closes[i]) || !near(adj.Volumes[i], q.Volumes[i]*volume) ||
+				!near(adj.Deliveries[i], q.Deliveries[i]*volume) {
+				t.Errorf("%s: session %d %v, want prices x%v quantities x%v of %v", c.name, i, adj.Quote(i), price, volume, q.Quote(i))
+			}
+		}
+	}
+}
+
+func TestPricesWithoutActions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "actions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// a 5:1 split on the third session and a dividend of 2% on the fifth
+	q := daily(100, 100, 20, 20, 19.6, 19.6)
+	for i, a := range []float64{19.208, 19.208, 19.208, 19.208, 19.6, 19.6} {
+		q.AdjCloses[i] = a
+	}
+	if raw, err := q.Prices(RawPrices, dir); err != nil || raw != q {
+		t.Errorf("raw prices %v, %v", raw, err)
+	}
+	adj, err := q.Prices(AdjustedPrices, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, volume := range []float64{5000, 5000, 1000, 1000, 1000, 1000} {
+		if !near(adj.Closes[i], q.AdjCloses[i]) || !near(adj.Volumes[i], volume) {
+			t.Errorf("session %d: close %v volume %v, want %v and %v", i, adj.Closes[i], adj.Volumes[i], q.AdjCloses[i], volume)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package quotes
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// The cache file holds a header describing the source file followed by the
+// quotes column by column and a crc32 of everything before it:
+//
+//	magic "QDC\x00", version uint32
+//	source mtime int64 (unix nanoseconds), source size int64, source sha256 [32]byte
+//	schema key, symbol
+//	time zone name, time zone offset int32 (seconds east of UTC)
+//	parsed uint32, skipped uint32, the skipped issues, repaired uint32, the repaired issues
+//	sessions uint32
+//	dates int64 (unix nanoseconds) x sessions
+//	opens, highs, lows, closes, adjcloses, volumes float64 x sessions each
+//	optional series uint8, bit s-1 set for each Series s held
+//	the optional series held, in the order of AllSeries, float64 x sessions each
+//	crc32 uint32
+//
+// Strings are a uint16 length followed by the bytes, an issue is its line
+// uint32, field and reason. All numbers are little endian.
+const (
+	cacheMagic   = "QDC\x00"
+	cacheVersion = 3
+	CacheExt     = ".qdc"
+)
+
+var ErrCacheVersion = errors.New("cache version mismatch")
+
+// SourceInfo identifies the file a cache was built from
+type SourceInfo struct {
+	ModTime time.Time
+	Size    int64
+	Hash    [sha256.Size]byte
+	Schema  string // key of the schema the file was loaded with
+}
+
+// schemaKey describes what of a schema changes the quotes loaded; a nil
+// schema, which is sniffed, has the empty key
+func schemaKey(s *Schema) string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("%q %q %d %t %q %s %v %q",
+		s.Delimiter, s.Comment, s.Skip, s.Header, s.DateLayout, s.Location, s.Columns, s.Nulls)
+}
+
+func statSource(file string) (SourceInfo, error) {
+	fi, err := os.Stat(file)
+	if err != nil {
+		return SourceInfo{}, err
+	}
+	return SourceInfo{ModTime: fi.ModTime(), Size: fi.Size()}, nil
+}
+
+func hashFile(file string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	f, err := os.Open(file)
+	if err != nil {
+		return sum, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return sum, err
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+// CacheFile returns the cache file of a quote file. The cache sits next to
+// the quote file when cacheDir is empty.
+func CacheFile(csvfile string, cacheDir string) string {
+	if cacheDir == "" {
+		return csvfile + CacheExt
+	}
+	return filepath.Join(cacheDir, filepath.Base(csvfile)+CacheExt)
+}
+
+// LoadCached loads the quotes from the cache of the file, rebuilding the
+// cache when the file or the schema has changed since. A file whose
+// modification time changed but whose content did not keeps its cache. A
+// nil schema is sniffed. The report of a cached file is the one of the load
+// that built the cache.
+func LoadCached(csvfile string, symbol string, schema *Schema, cacheDir string) (*QuoteData, *LoadReport, error) {
+	src, err := statSource(csvfile)
+	if err != nil {
+		return nil, nil, err
+	}
+	src.Schema = schemaKey(schema)
+	cachefile := CacheFile(csvfile, cacheDir)
+	qd, report, cached, err := readCacheFile(cachefile)
+	if err == nil && qd.Symbol == symbol && cached.Size == src.Size && cached.Schema == src.Schema {
+		fresh := cached.ModTime.Equal(src.ModTime)
+		if !fresh {
+			if src.Hash, err = hashFile(csvfile); err != nil {
+				return nil, nil, err
+			}
+			fresh = src.Hash == cached.Hash
+			if fresh {
+				writeCacheFile(cachefile, qd, report, src)
+			}
+		}
+		if fresh {
+			report.Symbol, report.Source = symbol, csvfile
+			report.Cached = true
+			return qd, report, nil
+		}
+	}
+
+	qd, report, err = LoadFileWithSchema(csvfile, symbol, schema)
+	if err != nil {
+		return qd, report, err
+	}
+	if src.Hash, err = hashFile(csvfile); err != nil {
+		return nil, nil, err
+	}
+	if err := writeCacheFile(cachefile, qd, report, src); err != nil {
+		return qd, report, fmt.Errorf("writing cache %s: %v", cachefile, err)
+	}
+	return qd, report, nil
+}
+
+func readCacheFile(cachefile string) (*QuoteData, *LoadReport, SourceInfo, error) {
+	f, err := os.Open(cachefile)
+	if err != nil {
+		return nil, nil, SourceInfo{}, err
+	}
+	defer f.Close()
+	return ReadCache(bufio.NewReader(f))
+}
+
+// writeCacheFile writes the cache to a temporary file and renames it into
+// place so that a reader never sees half a cache
+func writeCacheFile(cachefile string, qd *QuoteData, report *LoadReport, src SourceInfo) error {
+	dir := filepath.Dir(cachefile)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, filepath.Base(cachefile)+".*")
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	err = tmp.Chmod(0644)
+	if err == nil {
+		err = WriteCache(w, qd, report, src)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), cachefile)
+}
+
+// WriteCache encodes the quotes, the report of their load and the
+// description of their source
+func WriteCache(w io.Writer, qd *QuoteData, report *LoadReport, src SourceInfo) error {
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+	buf.WriteString(cacheMagic)
+	binary.Write(&buf, le, uint32(cacheVersion))
+	binary.Write(&buf, le, src.ModTime.UnixNano())
+	binary.Write(&buf, le, src.Size)
+	buf.Write(src.Hash[:])
+	loc := time.UTC
+	if len(qd.Dates) > 0 {
+		loc = qd.Dates[0].Location()
+	}
+	_, offset := time.Date(2000, 1, 1, 0, 0, 0, 0, loc).Zone()
+	err := writeString(&buf, src.Schema)
+	if err == nil {
+		err = writeString(&buf, qd.Symbol)
+	}
+	if err == nil {
+		err = writeString(&buf, loc.String())
+	}
+	binary.Write(&buf, le, int32(offset))
+	binary.Write(&buf, le, uint32(report.Parsed))
+	for _, issues := range [][]LineIssue{report.Skipped, report.Repaired} {
+		binary.Write(&buf, le, uint32(len(issues)))
+		for _, l := range issues {
+			binary.Write(&buf, le, uint32(l.Line))
+			if err == nil {
+				err = writeString(&buf, l.Field)
+			}
+			if err == nil {
+				err = writeString(&buf, l.Reason)
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", qd.Symbol, err)
+	}
+	n := len(qd.Dates)
+	binary.Write(&buf, le, uint32(n))
+	dates := make([]int64, n)
+	for i, d := range qd.Dates {
+		dates[i] = d.UnixNano()
+	}
+	binary.Write(&buf, le, dates)
+	adjCloses := qd.AdjCloses
+	if len(adjCloses) != n {
+		adjCloses = qd.Closes
+	}
+	for _, column := range [][]float64{qd.Opens, qd.Highs, qd.Lows, qd.Closes, adjCloses, qd.Volumes} {
+		if len(column) != n {
+			return fmt.Errorf("%s: columns of unequal length", qd.Symbol)
+		}
+		binary.Write(&buf, le, column)
+	}
+	var extras uint8
+	for _, e := range qd.Extras() {
+		extras |= 1 << uint(e-1)
+	}
+	buf.WriteByte(extras)
+	for _, e := range qd.Extras() {
+		if len(qd.Series(e)) != n {
+			return fmt.Errorf("%s: %s of unequal length", qd.Symbol, e)
+		}
+		binary.Write(&buf, le, qd.Series(e))
+	}
+	binary.Write(&buf, le, crc32.ChecksumIEEE(buf.Bytes()))
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func writeString(buf *bytes.Buffer, s string) error {
+	if len(s) > math.MaxUint16 {
+		return fmt.Errorf("string too long: %d bytes", len(s))
+	}
+	binary.Write(buf, binary.LittleEndian, uint16(len(s)))
+	buf.WriteString(s)
+	return nil
+}
+
+func readString(buf *bytes.Reader) (string, error) {
+	var n uint16
+	if err := binary.Read(buf, binary.LittleEndian, &n); err != nil {
+		return "", err
+	}
+	s := make([]byte, n)
+	if _, err := io.ReadFull(buf, s); err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+func readIssues(buf *bytes.Reader) ([]LineIssue, error) {
+	var n uint32
+	if err := binary.Read(buf, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	if int64(n)*8 > int64(buf.Len()) {
+		return nil, errors.New("cache size mismatch")
+	}
+	var issues []LineIssue
+	for i := uint32(0); i < n; i++ {
+		var l LineIssue
+		var line uint32
+		err := binary.Read(buf, binary.LittleEndian, &line)
+		if err == nil {
+			l.Field, err = readString(buf)
+		}
+		if err == nil {
+			l.Reason, err = readString(buf)
+		}
+		if err != nil {
+			return nil, err
+		}
+		l.Line = int(line)
+		issues = append(issues, l)
+	}
+	return issues, nil
+}
+
+// ReadCache decodes quotes and their report written by WriteCache,
+// checking the version and the checksum
+func ReadCache(r io.Reader) (*QuoteData, *LoadReport, SourceInfo, error) {
+	var src SourceInfo
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, src, err
+	}
+	if len(data) < len(cacheMagic)+4+4 || string(data[:len(cacheMagic)]) != cacheMagic {
+		return nil, nil, src, errors.New("not a quote cache")
+	}
+	le := binary.LittleEndian
+	body, sum := data[:len(data)-4], le.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return nil, nil, src, errors.New("cache checksum mismatch")
+	}
+	buf := bytes.NewReader(body[len(cacheMagic):])
+	var version uint32
+	binary.Read(buf, le, &version)
+	if version != cacheVersion {
+		return nil, nil, src, ErrCacheVersion
+	}
+	var modTime int64
+	var offset int32
+	var parsed, n uint32
+	var symbol, zone string
+	report := &LoadReport{}
+	binary.Read(buf, le, &modTime)
+	binary.Read(buf, le, &src.Size)
+	io.ReadFull(buf, src.Hash[:])
+	src.ModTime = time.Unix(0, modTime)
+	src.Schema, err = readString(buf)
+	if err == nil {
+		symbol, err = readString(buf)
+	}
+	if err == nil {
+		zone, err = readString(buf)
+	}
+	if err == nil {
+		binary.Read(buf, le, &offset)
+		err = binary.Read(buf, le, &parsed)
+	}
+	if err == nil {
+		report.Skipped, err = readIssues(buf)
+	}
+	if err == nil {
+		report.Repaired, err = readIssues(buf)
+	}
+	if err == nil {
+		err = binary.Read(buf, le, &n)
+	}
+	if err != nil {
+		return nil, nil, src, err
+	}
+	report.Symbol = symbol
+	report.Parsed = int(parsed)
+	if int64(n)*8*7+1 > int64(buf.Len()) {
+		return nil, nil, src, errors.New("cache size mismatch")
+	}
+	loc := time.UTC
+	switch zone {
+	case "UTC":
+	case "":
+		loc = time.FixedZone("", int(offset))
+	default:
+		loc = loadLocation(zone, int(offset))
+	}
+	dates := make([]int64, n)
+	binary.Read(buf, le, dates)
+	qd := &QuoteData{
+		Symbol:    symbol,
+		Dates:     make([]time.Time, n),
+		Opens:     make([]float64, n),
+		Highs:     make([]float64, n),
+		Lows:      make([]float64, n),
+		Closes:    make([]float64, n),
+		AdjCloses: make([]float64, n),
+		Volumes:   make([]float64, n),
+	}
+	for i, d := range dates {
+		qd.Dates[i] = time.Unix(0, d).In(loc)
+	}
+	for _, column := range [][]float64{qd.Opens, qd.Highs, qd.Lows, qd.Closes, qd.AdjCloses, qd.Volumes} {
+		binary.Read(buf, le, column)
+	}
+	extras, _ := buf.ReadByte()
+	var held []Series
+	for _, e := range AllSeries {
+		if extras&(1<<uint(e-1)) != 0 {
+			held = append(held, e)
+		}
+	}
+	if int64(n)*8*int64(len(held)) != int64(buf.Len()) {
+		return nil, nil, src, errors.New("cache size mismatch")
+	}
+	for _, e := range held {
+		values := make([]float64, n)
+		binary.Read(buf, le, values)
+		*qd.series(e) = values
+	}
+	return qd, report, src, nil
+}
@@ -0,0 +1,45 @@
+package quotes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadCached(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "TEST.csv")
+	data := "Date,Open,High,Low,Close,Volume\n" +
+		"2018-01-01 09:15,100.0,120.0,88.0,110.0,100000\n" +
+		"2018-01-01 09:20,100.0,120.0,x,110.0,100000\n" +
+		"2018-01-01 09:25,99.0,110.0,99.0,110.0,100000\n"
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	built, report, err := LoadCached(file, "TEST", nil, dir)
+	if err != nil || report.Cached || report.Clean() {
+		t.Fatalf("built %s, %v", report, err)
+	}
+	qd, cached, err := LoadCached(file, "TEST", nil, dir)
+	if err != nil || !cached.Cached || cached.Parsed != report.Parsed || len(cached.Skipped) != 1 || cached.Skipped[0] != report.Skipped[0] {
+		t.Fatalf("cached %s, %v", cached.Details(), err)
+	}
+	if qd.Dates[1].String() != built.Dates[1].String() {
+		t.Errorf("cached date %v, loaded %v", qd.Dates[1], built.Dates[1])
+	}
+
+	schema, err := SniffFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema.Location = nil
+	qd, report, err = LoadCached(file, "TEST", schema, dir)
+	if err != nil || report.Cached || qd.Dates[0].Location().String() != "UTC" {
+		t.Errorf("schema change: %s at %v, %v", report, qd.Dates[0], err)
+	}
+}
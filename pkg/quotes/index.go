@@ -0,0 +1,129 @@
+package quotes
+
+import (
+	"sort"
+	"time"
+)
+
+// Match says which session a date lookup returns
+type Match int
+
+const (
+	// Exact finds the session on the same calendar day, or the bar at the
+	// same time on a day holding several
+	Exact Match = iota
+	// Previous finds the session on the day or the last one before it
+	Previous
+	// Next finds the session on the day or the first one after it
+	Next
+)
+
+// truncateDay returns midnight UTC of the calendar day of t
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Index returns the position of the session matching the date, or -1.
+// Dates are compared by calendar day, so the time of day and the time zone
+// of the date do not matter, but for an Exact match on a day of intraday
+// bars, which compares the full timestamp. The quotes must be in date order.
+func (q *QuoteData) Index(date time.Time, m Match) int {
+	day := truncateDay(date)
+	i := sort.Search(len(q.Dates), func(i int) bool {
+		return !truncateDay(q.Dates[i]).Before(day)
+	})
+	exact := i < len(q.Dates) && truncateDay(q.Dates[i]).Equal(day)
+	if exact && m == Exact && i+1 < len(q.Dates) && truncateDay(q.Dates[i+1]).Equal(day) {
+		for ; i < len(q.Dates) && truncateDay(q.Dates[i]).Equal(day); i++ {
+			if q.Dates[i].Equal(date) {
+				return i
+			}
+		}
+		return -1
+	}
+	switch {
+	case exact:
+		return i
+	case m == Previous:
+		return i - 1
+	case m == Next && i < len(q.Dates):
+		return i
+	}
+	return -1
+}
+
+// Quote returns the i'th session
+func (q *QuoteData) Quote(i int) Quote {
+	quote := Quote{
+		Date:     q.Dates[i],
+		Open:     q.Opens[i],
+		High:     q.Highs[i],
+		Low:      q.Lows[i],
+		Close:    q.Closes[i],
+		AdjClose: q.Closes[i],
+		Volume:   q.Volumes[i],
+	}
+	if len(q.AdjCloses) == len(q.Closes) {
+		quote.AdjClose = q.AdjCloses[i]
+	}
+	for _, e := range q.Extras() {
+		*quote.value(e) = q.Series(e)[i]
+	}
+	return quote
+}
+
+// At returns the session on the calendar day of the date, or the intraday
+// bar at the date
+func (q *QuoteData) At(date time.Time) (Quote, bool) {
+	i := q.Index(date, Exact)
+	if i < 0 {
+		return Quote{}, false
+	}
+	return q.Quote(i), true
+}
+
+// Slice returns the sessions from one calendar day to another, both
+// included. The result shares its storage with q.
+func (q *QuoteData) Slice(from time.Time, to time.Time) *QuoteData {
+	start := q.Index(from, Next)
+	end := q.Index(to, Previous)
+	if start < 0 || end < start {
+		return &QuoteData{Symbol: q.Symbol}
+	}
+	return q.sub(start, end+1)
+}
+
+// Window returns the n sessions ending with the session on the date, or the
+// last session before it. Fewer sessions are returned near the start of the
+// quotes. The result shares its storage with q.
+func (q *QuoteData) Window(date time.Time, n int) *QuoteData {
+	end := q.Index(date, Previous)
+	if end < 0 || n <= 0 {
+		return &QuoteData{Symbol: q.Symbol}
+	}
+	start := end - n + 1
+	if start < 0 {
+		start = 0
+	}
+	return q.sub(start, end+1)
+}
+
+func (q *QuoteData) sub(start int, end int) *QuoteData {
+	r := &QuoteData{
+		Symbol:  q.Symbol,
+		Dates:   q.Dates[start:end],
+		Opens:   q.Opens[start:end],
+		Highs:   q.Highs[start:end],
+		Lows:    q.Lows[start:end],
+		Closes:  q.Closes[start:end],
+		Volumes: q.Volumes[start:end],
+	}
+	if len(q.AdjCloses) == len(q.Closes) {
+		r.AdjCloses = q.AdjCloses[start:end]
+	}
+	for _, e := range q.Extras() {
+		*r.series(e) = q.Series(e)[start:end]
+	}
+	return r
+}
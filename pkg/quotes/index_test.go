@@ -0,0 +1,119 @@
+package quotes
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIndexIntraday(t *testing.T) {
+	bar := func(d int, h int, m int) time.Time { return time.Date(2018, 1, d, h, m, 0, 0, IST) }
+	var qs []Quote
+	for _, d := range []time.Time{bar(1, 9, 15), bar(1, 9, 20), bar(1, 9, 25), bar(2, 9, 15), bar(2, 9, 20)} {
+		qs = append(qs, Quote{Date: d, Open: 100, High: 101, Low: 99, Close: 100, Volume: 1000})
+	}
+	qd := convert(qs, "TEST")
+	for _, c := range []struct {
+		date time.Time
+		m    Match
+		want int
+	}{
+		{bar(1, 9, 20), Exact, 1},
+		{bar(1, 9, 20).UTC(), Exact, 1},
+		{bar(1, 9, 25), Exact, 2},
+		{bar(1, 9, 22), Exact, -1},
+		{bar(2, 9, 15), Exact, 3},
+		{bar(3, 9, 15), Exact, -1},
+		{bar(3, 9, 15), Previous, 4},
+		{bar(1, 9, 22), Next, 0},
+	} {
+		if i := qd.Index(c.date, c.m); i != c.want {
+			t.Errorf("index of %v (%d): %d, want %d", c.date, c.m, i, c.want)
+		}
+	}
+
+	// a daily series still matches by day
+	daily := convert([]Quote{{Date: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}, {Date: time.Date(2018, 1, 2, 0, 0, 0, 0, time.UTC)}}, "TEST")
+	if i := daily.Index(bar(2, 15, 30), Exact); i != 1 {
+		t.Errorf("daily index %d", i)
+	}
+}
+
+func TestIndexDaily(t *testing.T) {
+	// sessions loaded at midnight in India, looked up by dates of trades.csv at midnight UTC
+	ist := func(d int) time.Time { return time.Date(2018, 1, d, 0, 0, 0, 0, IST) }
+	utc := func(d int) time.Time { return time.Date(2018, 1, d, 0, 0, 0, 0, time.UTC) }
+	var qs []Quote
+	// Wednesday the 3rd is a holiday
+	for i, d := range []time.Time{ist(2), ist(4), ist(5), ist(8)} {
+		c := float64(100 + i)
+		qs = append(qs, Quote{Date: d, Open: c, High: c, Low: c, Close: c, Volume: 1000})
+	}
+	qd := convert(qs, "TEST")
+	for _, c := range []struct {
+		date time.Time
+		m    Match
+		want int
+	}{
+		{utc(1), Exact, -1},
+		{utc(1), Previous, -1},
+		{utc(1), Next, 0},
+		{utc(3), Exact, -1},
+		{utc(3), Previous, 0},
+		{utc(3), Next, 1},
+		{utc(4), Exact, 1},
+		{time.Date(2018, 1, 4, 15, 30, 0, 0, IST), Exact, 1},
+		{time.Date(2018, 1, 4, 23, 0, 0, 0, time.UTC), Exact, 1}, // the 5th in India, the 4th as given
+		{utc(6), Previous, 2},
+		{utc(6), Next, 3},
+		{utc(9), Exact, -1},
+		{utc(9), Previous, 3},
+		{utc(9), Next, -1},
+	} {
+		if i := qd.Index(c.date, c.m); i != c.want {
+			t.Errorf("index of %v (%d): %d, want %d", c.date, c.m, i, c.want)
+		}
+	}
+
+	if q, ok := qd.At(utc(4)); !ok || q.Close != 101 {
+		t.Errorf("at the 4th: %v %v", q, ok)
+	}
+	if _, ok := qd.At(utc(3)); ok {
+		t.Error("a session on the holiday")
+	}
+
+	dates := func(q *QuoteData) []int {
+		var days []int
+		for _, d := range q.Dates {
+			days = append(days, d.Day())
+		}
+		return days
+	}
+	for _, c := range []struct {
+		name string
+		got  *QuoteData
+		want []int
+	}{
+		{"slice to a holiday", qd.Slice(utc(1), utc(3)), []int{2}},
+		{"slice from a holiday", qd.Slice(utc(3), utc(6)), []int{4, 5}},
+		{"slice of everything", qd.Slice(time.Date(2017, 12, 1, 0, 0, 0, 0, time.UTC), utc(31)), []int{2, 4, 5, 8}},
+		{"slice of the holiday", qd.Slice(utc(3), utc(3)), nil},
+		{"slice after the last", qd.Slice(utc(9), utc(20)), nil},
+		{"window on a holiday", qd.Window(utc(3), 5), []int{2}},
+		{"window after the last", qd.Window(utc(9), 2), []int{5, 8}},
+		{"window on a session", qd.Window(time.Date(2018, 1, 5, 15, 30, 0, 0, IST), 3), []int{2, 4, 5}},
+		{"window before the first", qd.Window(utc(1), 3), nil},
+		{"empty window", qd.Window(utc(6), 0), nil},
+	} {
+		got := dates(c.got)
+		if len(got) != len(c.want) || len(c.got.Closes) != len(c.want) {
+			t.Errorf("%s: %v, want %v", c.name, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s: %v, want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package quotes
+
+import (
+	"time"
+)
+
+// IST is the time zone of intraday NSE quotes
+var IST = loadLocation("Asia/Kolkata", 5*3600+30*60)
+
+func loadLocation(name string, offset int) *time.Location {
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc
+	}
+	return time.FixedZone(name, offset)
+}
+
+// Session is the trading window of an exchange day
+type Session struct {
+	Location *time.Location
+	Open     time.Duration // since midnight
+	Close    time.Duration // since midnight
+}
+
+// NSESession is the normal market of the NSE, 09:15 to 15:30 IST
+var NSESession = Session{
+	Location: IST,
+	Open:     9*time.Hour + 15*time.Minute,
+	Close:    15*time.Hour + 30*time.Minute,
+}
+
+// sinceMidnight returns the time of day of t in the session's time zone
+func (s Session) sinceMidnight(t time.Time) time.Duration {
+	t = t.In(s.Location)
+	y, m, d := t.Date()
+	return t.Sub(time.Date(y, m, d, 0, 0, 0, 0, s.Location))
+}
+
+// Contains returns true when a bar starting at t lies in the session;
+// bars of the pre-open and after the close do not
+func (s Session) Contains(t time.Time) bool {
+	since := s.sinceMidnight(t)
+	return since >= s.Open && since < s.Close
+}
+
+// IsIntraday returns true when any two sessions fall on the same calendar day
+func (q *QuoteData) IsIntraday() bool {
+	for i := 1; i < len(q.Dates); i++ {
+		if truncateDay(q.Dates[i]).Equal(truncateDay(q.Dates[i-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// InSession returns the bars that lie in the session
+func (q *QuoteData) InSession(s Session) *QuoteData {
+	keep := make([]int, 0, len(q.Dates))
+	for i, d := range q.Dates {
+		if s.Contains(d) {
+			keep = append(keep, i)
+		}
+	}
+	return q.pick(keep)
+}
+
+// Daily aggregates the intraday bars inside the session into one bar per
+// day, dated midnight UTC like the daily quote files
+func (q *QuoteData) Daily(s Session) *QuoteData {
+	r := &QuoteData{Symbol: q.Symbol}
+	in := q.InSession(s)
+	current := -1
+	for i, d := range in.Dates {
+		y, m, dd := d.In(s.Location).Date()
+		day := time.Date(y, m, dd, 0, 0, 0, 0, time.UTC)
+		if current == -1 || !day.Equal(r.Dates[current]) {
+			r.Dates = append(r.Dates, day)
+			r.Opens = append(r.Opens, in.Opens[i])
+			r.Highs = append(r.Highs, in.Highs[i])
+			r.Lows = append(r.Lows, in.Lows[i])
+			r.Closes = append(r.Closes, in.Closes[i])
+			r.AdjCloses = append(r.AdjCloses, in.AdjCloses[i])
+			r.Volumes = append(r.Volumes, in.Volumes[i])
+			r.appendExtras(in, i)
+			current++
+			continue
+		}
+		if in.Highs[i] > r.Highs[current] {
+			r.Highs[current] = in.Highs[i]
+		}
+		if in.Lows[i] < r.Lows[current] {
+			r.Lows[current] = in.Lows[i]
+		}
+		r.Closes[current] = in.Closes[i]
+		r.AdjCloses[current] = in.AdjCloses[i]
+		r.Volumes[current] += in.Volumes[i]
+		r.addExtras(current, in, i)
+	}
+	r.finishExtras()
+	return r
+}
@@ -0,0 +1,147 @@
+package quotes
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ConflictPolicy says which session wins when two series disagree on a day
+type ConflictPolicy int
+
+const (
+	PreferNewest ConflictPolicy = iota + 1
+	PreferExisting
+	FailOnConflict
+)
+
+// Conflict is a day on which the existing and the incoming quotes differ
+type Conflict struct {
+	Date     time.Time
+	Existing Quote
+	Incoming Quote
+}
+
+func (c Conflict) String() string {
+	e, n := c.Existing, c.Incoming
+	return fmt.Sprintf("%s existing O:%.2f H:%.2f L:%.2f C:%.2f V:%.0f incoming O:%.2f H:%.2f L:%.2f C:%.2f V:%.0f",
+		c.Date.Format("2006-01-02"), e.Open, e.High, e.Low, e.Close, e.Volume, n.Open, n.High, n.Low, n.Close, n.Volume)
+}
+
+// MergeReport describes the outcome of a merge
+type MergeReport struct {
+	Symbol    string
+	Existing  int // sessions in the existing series
+	Added     int // days only the incoming series has
+	Same      int // days both series agree on
+	Skipped   int // rows of the existing file that could not be read
+	Conflicts []Conflict
+	Policy    ConflictPolicy
+}
+
+func (r *MergeReport) String() string {
+	return fmt.Sprintf("%s: existing:%d added:%d same:%d skipped:%d conflicts:%d",
+		r.Symbol, r.Existing, r.Added, r.Same, r.Skipped, len(r.Conflicts))
+}
+
+// sameQuote compares open, high, low, close and volume. AdjClose is left
+// out because every new dividend rewrites the whole adjusted history.
+func sameQuote(a Quote, b Quote) bool {
+	same := func(x, y float64) bool {
+		return math.Abs(x-y) <= 1e-6*math.Max(math.Abs(x), math.Abs(y))
+	}
+	return same(a.Open, b.Open) && same(a.High, b.High) && same(a.Low, b.Low) &&
+		same(a.Close, b.Close) && same(a.Volume, b.Volume)
+}
+
+// Merge combines an existing series with a newer download into one session
+// per day, or per timestamp when either holds intraday bars. Days on which the two differ are resolved by the policy; with
+// FailOnConflict an error is returned along with the report of the conflicts.
+// With PreferExisting the days the two agree on keep their existing
+// adjusted close too.
+func Merge(existing *QuoteData, incoming *QuoteData, policy ConflictPolicy) (*QuoteData, *MergeReport, error) {
+	key := sessionKey(existing, incoming)
+	a, b := sortedUnique(existing, key), sortedUnique(incoming, key)
+	report := &MergeReport{Symbol: existing.Symbol, Existing: len(a.Dates), Policy: policy}
+	var merged []Quote
+	i, j := 0, 0
+	for i < len(a.Dates) || j < len(b.Dates) {
+		switch {
+		case j == len(b.Dates) || (i < len(a.Dates) && key(a.Dates[i]).Before(key(b.Dates[j]))):
+			merged = append(merged, a.Quote(i))
+			i++
+		case i == len(a.Dates) || key(b.Dates[j]).Before(key(a.Dates[i])):
+			merged = append(merged, b.Quote(j))
+			report.Added++
+			j++
+		default:
+			e, n := a.Quote(i), b.Quote(j)
+			// a download without delivery or open interest columns keeps
+			// the values the existing series has for the day
+			for _, s := range a.Extras() {
+				if !b.Has(s) {
+					*n.value(s) = *e.value(s)
+				}
+			}
+			if sameQuote(e, n) {
+				report.Same++
+				if policy == PreferExisting {
+					n.AdjClose = e.AdjClose
+				}
+				merged = append(merged, n)
+			} else {
+				report.Conflicts = append(report.Conflicts, Conflict{Date: e.Date, Existing: e, Incoming: n})
+				if policy == PreferExisting {
+					merged = append(merged, e)
+				} else {
+					merged = append(merged, n)
+				}
+			}
+			i++
+			j++
+		}
+	}
+	if policy == FailOnConflict && len(report.Conflicts) > 0 {
+		return nil, report, fmt.Errorf("%s: %d conflicting sessions, first on %s", report.Symbol,
+			len(report.Conflicts), report.Conflicts[0].Date.Format("2006-01-02"))
+	}
+	var extras []Series
+	for _, e := range AllSeries {
+		if existing.Has(e) || incoming.Has(e) {
+			extras = append(extras, e)
+		}
+	}
+	return convert(merged, existing.Symbol, extras...), report, nil
+}
+
+// MergeFile merges a newer download into a quote file and writes the file
+// back in its own format, with columns added for the optional series only
+// the download has. The file is left untouched when the merge fails
+// or when some of its rows could not be read, as writing it back would
+// lose them.
+func MergeFile(file string, newfile string, symbol string, policy ConflictPolicy) (*MergeReport, error) {
+	schema, err := SniffFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	existing, loaded, err := LoadFileWithSchema(file, symbol, schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(loaded.Skipped) > 0 {
+		report := &MergeReport{Symbol: symbol, Existing: len(existing.Dates), Skipped: len(loaded.Skipped), Policy: policy}
+		first := loaded.Skipped[0]
+		return report, fmt.Errorf("%s: %d rows could not be read, the first on line %d (%s); fix them before merging",
+			file, len(loaded.Skipped), first.Line, first.Reason)
+	}
+	incoming, _, err := LoadFileWithSchema(newfile, symbol, nil)
+	if err != nil {
+		return nil, err
+	}
+	merged, report, err := Merge(existing, incoming, policy)
+	if err != nil {
+		return report, err
+	}
+	schema.AddColumns(merged.Extras()...)
+	return report, schema.WriteFile(file, merged)
+}
@@ -0,0 +1,72 @@
+package quotes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file, newfile := filepath.Join(dir, "TEST.csv"), filepath.Join(dir, "new.csv")
+	header := "Date,Open,High,Low,Close,Adj Close,Volume\n"
+	rows := []string{
+		"01-01-2018,100.0,120.0,88.0,110.0,109.0,100000\n",
+		"02-01-2018,100.0,120.0,bad,110.0,109.0,100000\n",
+		"03-01-2018,99.0,110.0,99.0,110.0,109.0,100000\n",
+	}
+	existing := header + strings.Join(rows, "")
+	update := header + rows[2] + "04-01-2018,110.0,112.0,108.0,111.0,111.0,100000\n"
+	write := func(file string, data string) {
+		if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(file, existing)
+	write(newfile, update)
+
+	report, err := MergeFile(file, newfile, "TEST", PreferNewest)
+	if err == nil || report == nil || report.Skipped != 1 {
+		t.Fatalf("merged into a file with a bad row: %v, %v", report, err)
+	}
+	if data, _ := ioutil.ReadFile(file); string(data) != existing {
+		t.Errorf("file rewritten:\n%s", data)
+	}
+
+	write(file, header+rows[0]+rows[2])
+	report, err = MergeFile(file, newfile, "TEST", PreferNewest)
+	if err != nil || report.Added != 1 || report.Same != 1 || report.Skipped != 0 {
+		t.Fatalf("merge %v, %v", report, err)
+	}
+	qd, loaded, err := LoadFileWithSchema(file, "TEST", nil)
+	if err != nil || !loaded.Clean() || len(qd.Dates) != 3 || qd.Closes[2] != 111 {
+		t.Errorf("merged file %v, %s, %v", qd, loaded, err)
+	}
+}
+
+func TestMergeIntraday(t *testing.T) {
+	bar := func(h int, m int) time.Time { return time.Date(2018, 1, 1, h, m, 0, 0, IST) }
+	bars := func(close float64, times ...time.Time) *QuoteData {
+		var qs []Quote
+		for _, d := range times {
+			qs = append(qs, Quote{Date: d, Open: 100, High: 112, Low: 99, Close: close, Volume: 1000})
+		}
+		return convert(qs, "TEST")
+	}
+	existing := bars(100, bar(9, 15), bar(9, 20), bar(9, 25))
+	incoming := bars(110, bar(9, 25), bar(9, 30), bar(9, 35))
+	merged, report, err := Merge(existing, incoming, PreferExisting)
+	if err != nil || report.Added != 2 || len(report.Conflicts) != 1 {
+		t.Fatalf("merge %v, %v", report, err)
+	}
+	if len(merged.Dates) != 5 || !merged.Dates[4].Equal(bar(9, 35)) || merged.Closes[2] != 100 || merged.Closes[3] != 110 {
+		t.Errorf("merged %v %v", merged.Dates, merged.Closes)
+	}
+}
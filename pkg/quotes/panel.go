@@ -0,0 +1,168 @@
+package quotes
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MissingPolicy says what a Panel does with a date a symbol has no quote for
+type MissingPolicy int
+
+const (
+	// LeaveGap keeps the date and marks the quote as NaN
+	LeaveGap MissingPolicy = iota
+	// ForwardFill repeats the previous close as open, high, low and close with no volume
+	ForwardFill
+	// DropDate keeps only the dates every symbol has a quote for
+	DropDate
+)
+
+// Panel holds the quotes of many symbols on a common date index
+type Panel struct {
+	Dates   []time.Time
+	Symbols []string
+	Policy  MissingPolicy
+	series  map[string]*QuoteData
+	filled  map[string][]bool // sessions ForwardFill made up
+	index   map[time.Time]int
+}
+
+// NewPanel aligns the quotes on the union of their dates, or their
+// intersection with DropDate. Dates are compared by calendar day.
+func NewPanel(data []*QuoteData, policy MissingPolicy) *Panel {
+	count := make(map[time.Time]int)
+	for _, qd := range data {
+		seen := make(map[time.Time]bool, len(qd.Dates))
+		for _, d := range qd.Dates {
+			d = truncateDay(d)
+			if !seen[d] {
+				seen[d] = true
+				count[d]++
+			}
+		}
+	}
+	p := &Panel{
+		Policy: policy,
+		series: make(map[string]*QuoteData, len(data)),
+		filled: make(map[string][]bool, len(data)),
+		index:  make(map[time.Time]int, len(count)),
+	}
+	for d, n := range count {
+		if policy == DropDate && n < len(data) {
+			continue
+		}
+		p.Dates = append(p.Dates, d)
+	}
+	sort.Slice(p.Dates, func(i, j int) bool { return p.Dates[i].Before(p.Dates[j]) })
+	for i, d := range p.Dates {
+		p.index[d] = i
+	}
+	for _, qd := range data {
+		p.Symbols = append(p.Symbols, qd.Symbol)
+		p.series[qd.Symbol], p.filled[qd.Symbol] = p.align(qd)
+	}
+	return p
+}
+
+// align returns the quotes on the dates of the panel, each column with its
+// own copy of the dates, and the sessions forward filled
+func (p *Panel) align(qd *QuoteData) (*QuoteData, []bool) {
+	n := len(p.Dates)
+	a := &QuoteData{
+		Symbol:    qd.Symbol,
+		Dates:     append([]time.Time(nil), p.Dates...),
+		Opens:     nanSeries(n),
+		Highs:     nanSeries(n),
+		Lows:      nanSeries(n),
+		Closes:    nanSeries(n),
+		AdjCloses: nanSeries(n),
+		Volumes:   nanSeries(n),
+	}
+	for _, e := range qd.Extras() {
+		*a.series(e) = nanSeries(n)
+	}
+	hasAdj := len(qd.AdjCloses) == len(qd.Closes)
+	for i, d := range qd.Dates {
+		j, ok := p.index[truncateDay(d)]
+		if !ok {
+			continue
+		}
+		a.Opens[j] = qd.Opens[i]
+		a.Highs[j] = qd.Highs[i]
+		a.Lows[j] = qd.Lows[i]
+		a.Closes[j] = qd.Closes[i]
+		a.AdjCloses[j] = qd.Closes[i]
+		if hasAdj {
+			a.AdjCloses[j] = qd.AdjCloses[i]
+		}
+		a.Volumes[j] = qd.Volumes[i]
+		for _, e := range qd.Extras() {
+			a.Series(e)[j] = qd.Series(e)[i]
+		}
+	}
+	filled := make([]bool, n)
+	if p.Policy == ForwardFill {
+		for j := 1; j < n; j++ {
+			if !math.IsNaN(a.Closes[j]) || math.IsNaN(a.Closes[j-1]) {
+				continue
+			}
+			a.Opens[j] = a.Closes[j-1]
+			a.Highs[j] = a.Closes[j-1]
+			a.Lows[j] = a.Closes[j-1]
+			a.Closes[j] = a.Closes[j-1]
+			a.AdjCloses[j] = a.AdjCloses[j-1]
+			a.Volumes[j] = 0
+			fillExtras(a, j)
+			filled[j] = true
+		}
+	}
+	return a, filled
+}
+
+func nanSeries(n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = math.NaN()
+	}
+	return s
+}
+
+// Index returns the position of the date in the panel, or -1
+func (p *Panel) Index(date time.Time) int {
+	if i, ok := p.index[truncateDay(date)]; ok {
+		return i
+	}
+	return -1
+}
+
+// Column returns the aligned quotes of a symbol, or nil when it is not in the panel
+func (p *Panel) Column(symbol string) *QuoteData {
+	return p.series[symbol]
+}
+
+// CrossSection returns the quote of every symbol on the i'th date, in the
+// order of Symbols. Missing quotes hold NaN.
+func (p *Panel) CrossSection(i int) []Quote {
+	cs := make([]Quote, len(p.Symbols))
+	for k, s := range p.Symbols {
+		cs[k] = p.series[s].Quote(i)
+	}
+	return cs
+}
+
+// Closes returns the close of every symbol on the i'th date, in the order of Symbols
+func (p *Panel) Closes(i int) []float64 {
+	closes := make([]float64, len(p.Symbols))
+	for k, s := range p.Symbols {
+		closes[k] = p.series[s].Closes[i]
+	}
+	return closes
+}
+
+// Missing returns true when the symbol has no quote on the i'th date,
+// forward filled or not
+func (p *Panel) Missing(symbol string, i int) bool {
+	qd, ok := p.series[symbol]
+	return !ok || math.IsNaN(qd.Closes[i]) || p.filled[symbol][i]
+}
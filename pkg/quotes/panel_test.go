@@ -0,0 +1,20 @@
+package quotes
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPanelForwardFill(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2018, 1, d, 0, 0, 0, 0, time.UTC) }
+	a := convert([]Quote{{Date: day(1), Close: 10}, {Date: day(2), Close: 11}, {Date: day(3), Close: 12}}, "A")
+	b := convert([]Quote{{Date: day(1), Close: 20}, {Date: day(3), Close: 22}}, "B")
+	p := NewPanel([]*QuoteData{a, b}, ForwardFill)
+	if p.Column("B").Closes[1] != 20 || !p.Missing("B", 1) || p.Missing("B", 2) || p.Missing("A", 1) {
+		t.Errorf("B closes %v, missing %v %v", p.Column("B").Closes, p.Missing("B", 1), p.Missing("B", 2))
+	}
+	p.Column("A").Dates[0] = day(9)
+	if !p.Column("B").Dates[0].Equal(day(1)) || !p.Dates[0].Equal(day(1)) {
+		t.Error("columns share their dates")
+	}
+}
@@ -0,0 +1,76 @@
+package quotes
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"pkg/symbols"
+)
+
+// Pipeline loads the quotes of a symbol the same way for every command:
+// through the cache, validated and repaired, then priced
+type Pipeline struct {
+	Dir        string       // folder of the quote files
+	Ext        string       // file name suffix after the symbol, ".csv" when empty
+	CacheDir   string       // no caching when empty
+	Schema     *Schema      // sniffed when nil
+	Validator  *Validator   // no validation when nil
+	Repair     RepairPolicy // bad sessions are dropped when 0
+	Mode       PriceMode
+	ActionsDir string          // corporate action files, Dir when empty
+	Symbols    *symbols.Master // resolves names and finds files under any of them when set
+}
+
+// File returns the quote file of a symbol
+func (p *Pipeline) File(symbol string) string {
+	ext := p.Ext
+	if ext == "" {
+		ext = ".csv"
+	}
+	return filepath.Join(p.Dir, symbol+ext)
+}
+
+// find returns the ticker a name stands for and its quote file. With a
+// symbol master the file may be named after any name of the symbol, such
+// as BAJFINANCE.csv or BAJFINANCE.NS.csv.
+func (p *Pipeline) find(name string) (string, string) {
+	if p.Symbols == nil {
+		return name, p.File(name)
+	}
+	s := p.Symbols.Resolve(name)
+	for _, n := range s.Names() {
+		if _, err := os.Stat(p.File(n)); err == nil {
+			return s.Symbol, p.File(n)
+		}
+	}
+	return s.Symbol, p.File(s.Symbol)
+}
+
+// Load returns the quotes of the symbol along with the report of the file
+func (p *Pipeline) Load(name string) (*QuoteData, *LoadReport, error) {
+	var qd *QuoteData
+	var report *LoadReport
+	var err error
+	symbol, file := p.find(name)
+	if p.CacheDir == "" {
+		qd, report, err = LoadFileWithSchema(file, symbol, p.Schema)
+	} else {
+		qd, report, err = LoadCached(file, symbol, p.Schema, p.CacheDir)
+	}
+	if err != nil {
+		return nil, report, err
+	}
+	if p.Validator != nil {
+		var validation *ValidationReport
+		qd, validation = p.Validator.Repair(qd, p.Repair)
+		if len(validation.Issues) > 0 {
+			log.Print(validation)
+		}
+	}
+	actionsDir := p.ActionsDir
+	if actionsDir == "" {
+		actionsDir = p.Dir
+	}
+	qd, err = qd.Prices(p.Mode, actionsDir)
+	return qd, report, err
+}
@@ -8,17 +8,24 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type QuoteData struct {
-	Symbol  string
-	Dates   []time.Time
-	Opens   []float64
-	Highs   []float64
-	Lows    []float64
-	Closes  []float64
-	Volumes []float64
+	Symbol    string
+	Dates     []time.Time
+	Opens     []float64
+	Highs     []float64
+	Lows      []float64
+	Closes    []float64
+	AdjCloses []float64
+	Volumes   []float64
+	// optional series, nil when the source has no such column
+	Deliveries    []float64
+	DeliveryPcts  []float64
+	Turnovers     []float64
+	OpenInterests []float64
 }
 
 type Quote struct {
@@ -29,17 +36,41 @@ type Quote struct {
 	Close    float64
 	AdjClose float64
 	Volume   float64
+	// optional values, 0 when the quotes do not have the series
+	Delivery     float64
+	DeliveryPct  float64
+	Turnover     float64
+	OpenInterest float64
 }
 
+// LoadFromFile loads the quotes and logs the rows that could not be used.
+// Use LoadFile when the caller needs to know that the file could not be read.
 func LoadFromFile(csvfile string, symbol string, skip int) *QuoteData {
+	qd, report, err := LoadFile(csvfile, symbol, skip)
+	if err != nil {
+		log.Printf("Unable to load %s: %v", csvfile, err)
+		return &QuoteData{Symbol: symbol}
+	}
+	logReport(report)
+	return qd
+}
+
+// LoadFile loads the quotes of a symbol from a csv file, skipping the first
+// skip lines. The report lists the rows that were skipped or repaired.
+func LoadFile(csvfile string, symbol string, skip int) (*QuoteData, *LoadReport, error) {
 	csvreader, err := os.Open(csvfile)
 	if err != nil {
-		log.Printf("Unable to open %s: %v", csvfile, err)
+		return nil, nil, err
 	}
+	defer csvreader.Close()
 	reader := csv.NewReader(bufio.NewReader(csvreader))
 	reader.ReuseRecord = true
 	reader.FieldsPerRecord = -1
-	return Load(reader, symbol, skip)
+	qd, report, err := LoadReader(reader, symbol, skip)
+	if report != nil {
+		report.Source = csvfile
+	}
+	return qd, report, err
 }
 
 func (q *QuoteData) String() string {
@@ -48,74 +79,173 @@ func (q *QuoteData) String() string {
 
 }
 
+// Load reads the quotes and logs the rows that could not be used.
 func Load(reader *csv.Reader, symbol string, skip int) *QuoteData {
+	qd, report, err := LoadReader(reader, symbol, skip)
+	if err != nil {
+		log.Printf("error reading file: %v", err)
+	}
+	logReport(report)
+	return qd
+}
+
+// LoadReader reads the quotes of a symbol, skipping the first skip lines.
+// Lines that cannot be parsed are skipped and "null" fields are replaced
+// by 0; both are recorded in the report. A read error stops the load and
+// is returned along with the quotes read so far.
+func LoadReader(reader *csv.Reader, symbol string, skip int) (*QuoteData, *LoadReport, error) {
+	return DefaultSchema(skip).Load(reader, symbol)
+}
+
+// LoadFileWithSchema loads the quotes of a symbol from a file of the given
+// schema. A nil schema is sniffed from the start of the file.
+func LoadFileWithSchema(csvfile string, symbol string, schema *Schema) (*QuoteData, *LoadReport, error) {
+	f, err := os.Open(csvfile)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	qd, report, err := LoadWithSchema(f, symbol, schema)
+	if report != nil {
+		report.Source = csvfile
+	}
+	return qd, report, err
+}
+
+// LoadWithSchema reads the quotes of a symbol. A nil schema is sniffed
+// from the start of the data.
+func LoadWithSchema(r io.Reader, symbol string, schema *Schema) (*QuoteData, *LoadReport, error) {
+	if schema == nil {
+		br := bufio.NewReaderSize(r, sniffSize)
+		sample, err := br.Peek(sniffSize)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return nil, nil, err
+		}
+		if schema, err = SniffSchema(sample); err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", symbol, err)
+		}
+		r = br
+	}
+	return schema.Load(schema.NewReader(r), symbol)
+}
+
+// Load reads the quotes of a symbol from a reader set up for the schema.
+// Issues are reported by the line of the file their record starts on.
+func (s *Schema) Load(reader *csv.Reader, symbol string) (*QuoteData, *LoadReport, error) {
 	var quotes []Quote
-	var lineno = 0
+	var records = 0
+	report := newLoadReport(symbol, "")
+	width := s.width()
 	for {
-		lineno++
+		records++
 		line, err := reader.Read()
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			log.Printf("error reading file: %v", err)
-			break
+			return convert(quotes, symbol), report, err
 		}
-		if skip > 0 && lineno <= skip {
+		lineno, _ := reader.FieldPos(0)
+		if s.Skip > 0 && records <= s.Skip {
 			continue
 		}
-		for i := 0; i < len(line); i++ {
-			if line[i] == "null" {
-				line[i] = "0"
-			}
-		}
-		var q = Quote{}
-		if q.Date, err = time.Parse("02-01-2006", line[0]); err != nil {
-			log.Printf("%d: (Date) %v", lineno, err)
+		if s.Header && records == s.Skip+1 {
 			continue
 		}
-		if q.Open, err = strconv.ParseFloat(line[1], 64); err != nil {
-			log.Printf("%d: (Open) %v", lineno, err)
+		if s.isComment(line) {
 			continue
 		}
-		if q.High, err = strconv.ParseFloat(line[2], 64); err != nil {
-			log.Printf("%d: (High) %v", lineno, err)
+		if len(line) < width {
+			report.skip(lineno, "", fmt.Sprintf("expected %d fields, found %d", width, len(line)))
 			continue
 		}
-		if q.Low, err = strconv.ParseFloat(line[3], 64); err != nil {
-			log.Printf("%d: (Low) %v", lineno, err)
+		q, ok := s.parseQuote(line, lineno, report)
+		if !ok {
 			continue
 		}
-		if q.Close, err = strconv.ParseFloat(line[4], 64); err != nil {
-			log.Printf("%d: (Close) %v", lineno, err)
+		quotes = append(quotes, q)
+		report.Parsed++
+	}
+	qd := convert(quotes, symbol, s.Columns.extras()...)
+	qd.deriveDeliveryPct()
+	return qd, report, nil
+}
+
+func (s *Schema) parseQuote(line []string, lineno int, report *LoadReport) (Quote, bool) {
+	var q = Quote{}
+	var err error
+	c := s.Columns
+	if q.Date, err = s.parseDate(strings.TrimSpace(line[c.Date])); err != nil {
+		report.skip(lineno, "Date", err.Error())
+		return q, false
+	}
+	columns := []int{c.Open, c.High, c.Low, c.Close, c.AdjClose, c.Volume}
+	values := []*float64{&q.Open, &q.High, &q.Low, &q.Close, &q.AdjClose, &q.Volume}
+	var nulls []string
+	for i, v := range values {
+		if columns[i] < 0 {
 			continue
 		}
-		if q.AdjClose, err = strconv.ParseFloat(line[5], 64); err != nil {
-			log.Printf("%d: (AdjClose) %v", lineno, err)
+		field := strings.TrimSpace(line[columns[i]])
+		if s.isNull(field) {
+			nulls = append(nulls, quoteFields[i+1])
 			continue
 		}
-		if q.Volume, err = strconv.ParseFloat(line[6], 64); err != nil {
-			log.Printf("%d: (Volume) %v", lineno, err)
+		if *v, err = strconv.ParseFloat(field, 64); err != nil {
+			report.skip(lineno, quoteFields[i+1], err.Error())
+			return q, false
+		}
+	}
+	if c.AdjClose < 0 {
+		q.AdjClose = q.Close
+	}
+	// optional series are often blank, as for securities without
+	// delivery data, so their nulls are not reported
+	for _, e := range c.extras() {
+		field := strings.TrimSpace(line[c.column(e)])
+		if s.isNull(field) || field == "" || field == "-" {
 			continue
 		}
-		quotes = append(quotes, q)
+		if *q.value(e), err = strconv.ParseFloat(field, 64); err != nil {
+			report.skip(lineno, e.String(), err.Error())
+			return q, false
+		}
+	}
+	if len(nulls) > 0 {
+		report.repair(lineno, strings.Join(nulls, ","), "null replaced by 0")
 	}
-	return convert(quotes, symbol)
+	return q, true
 }
 
-func convert(quotes []Quote, symbol string) *QuoteData {
+var quoteFields = []string{"Date", "Open", "High", "Low", "Close", "AdjClose", "Volume"}
+
+func logReport(report *LoadReport) {
+	if report == nil {
+		return
+	}
+	for _, l := range report.Skipped {
+		log.Printf("%s %s", report.Symbol, l)
+	}
+	if len(report.Repaired) > 0 {
+		log.Printf("%s: %d rows with null fields replaced by 0", report.Symbol, len(report.Repaired))
+	}
+}
+
+// convert turns sessions into quotes holding the given optional series
+func convert(quotes []Quote, symbol string, extras ...Series) *QuoteData {
 	if len(quotes) == 0 {
 		return &QuoteData{
 			Symbol: symbol,
 		}
 	}
 	qd := &QuoteData{
-		Symbol:  symbol,
-		Dates:   make([]time.Time, len(quotes)),
-		Opens:   make([]float64, len(quotes)),
-		Highs:   make([]float64, len(quotes)),
-		Lows:    make([]float64, len(quotes)),
-		Closes:  make([]float64, len(quotes)),
-		Volumes: make([]float64, len(quotes)),
+		Symbol:    symbol,
+		Dates:     make([]time.Time, len(quotes)),
+		Opens:     make([]float64, len(quotes)),
+		Highs:     make([]float64, len(quotes)),
+		Lows:      make([]float64, len(quotes)),
+		Closes:    make([]float64, len(quotes)),
+		AdjCloses: make([]float64, len(quotes)),
+		Volumes:   make([]float64, len(quotes)),
 	}
 	for i, q := range quotes {
 		qd.Dates[i] = q.Date
@@ -123,7 +253,33 @@ func convert(quotes []Quote, symbol string) *QuoteData {
 		qd.Highs[i] = q.High
 		qd.Lows[i] = q.Low
 		qd.Closes[i] = q.Close
+		qd.AdjCloses[i] = q.AdjClose
 		qd.Volumes[i] = q.Volume
 	}
+	for _, e := range extras {
+		values := make([]float64, len(quotes))
+		for i := range quotes {
+			values[i] = *quotes[i].value(e)
+		}
+		*qd.series(e) = values
+	}
 	return qd
 }
+
+// Clone returns a deep copy of the quotes
+func (q *QuoteData) Clone() *QuoteData {
+	c := &QuoteData{
+		Symbol:    q.Symbol,
+		Dates:     append([]time.Time(nil), q.Dates...),
+		Opens:     append([]float64(nil), q.Opens...),
+		Highs:     append([]float64(nil), q.Highs...),
+		Lows:      append([]float64(nil), q.Lows...),
+		Closes:    append([]float64(nil), q.Closes...),
+		AdjCloses: append([]float64(nil), q.AdjCloses...),
+		Volumes:   append([]float64(nil), q.Volumes...),
+	}
+	for _, e := range q.Extras() {
+		*c.series(e) = append([]float64(nil), q.Series(e)...)
+	}
+	return c
+}
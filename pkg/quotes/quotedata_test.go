@@ -1,19 +1,75 @@
 package quotes
 
 import (
+	"strings"
 	"testing"
-	"errors"
-	"encoding/csv"
+	"time"
 )
 
+func TestConvert(t *testing.T) {
+	var quotes = []Quote{
+		Quote{Date: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Open: 100, High: 120, Low: 88, Close: 110, Volume: 100000},
+		Quote{Date: time.Date(2018, 1, 2, 0, 0, 0, 0, time.UTC), Open: 109, High: 121, Low: 100, Close: 111, Volume: 200000},
+		Quote{Date: time.Date(2018, 1, 3, 0, 0, 0, 0, time.UTC), Open: 99, High: 110, Low: 99, Close: 110, Volume: 100000},
+		Quote{Date: time.Date(2018, 1, 4, 0, 0, 0, 0, time.UTC), Open: 120, High: 113, Low: 99, Close: 100, Volume: 200000},
+	}
+	qd := convert(quotes, "TEST")
+	if qd.Symbol != "TEST" || len(qd.Dates) != len(quotes) {
+		t.Fatalf("got %s with %d sessions", qd.Symbol, len(qd.Dates))
+	}
+	for i, q := range quotes {
+		if qd.Quote(i) != q {
+			t.Errorf("session %d: got %v, want %v", i, qd.Quote(i), q)
+		}
+	}
+}
+
+func TestLoadWithSchema(t *testing.T) {
+	data := "Date,Open,High,Low,Close,Adj Close,Volume\r\n" +
+		"01-01-2018,100.0,120.0,88.0,110.0,109.0,100000\r\n" +
+		"02-01-2018,null,null,null,null,null,null\r\n" +
+		"03-01-2018,99.0,110.0,99.0,110.0,109.0,100000\r\n"
+	qd, report, err := LoadWithSchema(strings.NewReader(data), "TEST", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qd.Dates) != 3 || report.Parsed != 3 || len(report.Repaired) != 1 {
+		t.Fatalf("got %d sessions, report %s", len(qd.Dates), report)
+	}
+	if !qd.Dates[2].Equal(time.Date(2018, 1, 3, 0, 0, 0, 0, time.UTC)) || qd.AdjCloses[0] != 109 {
+		t.Errorf("got %v adjclose %.2f", qd.Dates[2], qd.AdjCloses[0])
+	}
+}
+
+func TestLoadReportLines(t *testing.T) {
+	data := "# exported quotes\n" +
+		"\n" +
+		"Date,Open,High,Low,Close,Adj Close,Volume\n" +
+		"01-01-2018,100.0,120.0,88.0,110.0,109.0,100000\n" +
+		"\"02-01-2018\n\",100.0,120.0,88.0,110.0,109.0,100000\n" +
+		"03-01-2018,99.0,110.0,x,110.0,109.0,100000\n"
+	_, report, err := LoadWithSchema(strings.NewReader(data), "TEST", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].Line != 7 || report.Skipped[0].Field != "Low" {
+		t.Errorf("skipped %v, want line 7 (Low)", report.Skipped)
+	}
+}
 
-func testload(t *Testing, err error) {
-	var quotes = []Quote {
-		Quote { Date: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Open: 100, High: 120, Low: 88, Close: 110, Volume: 100000 },
-		Quote { Date: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Open: 109, High: 121, Low: 100, Close: 111, Volume: 200000 },
-		Quote { Date: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Open: 99, High: 110, Low: 99, Close: 110, Volume: 100000 },
-		Quote { Date: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Open: 120, High: 113, Low: 99, Close: 100, Volume: 200000 },
-	}
-	qd := convert(quotes)
-	t.FailNow()
+func TestSniffDateColumn(t *testing.T) {
+	data := "Symbol,Open,High,Low,Close,Volume,Date\n" +
+		"TEST,100.0,120.0,88.0,110.0,100000,2018-01-01\n" +
+		"TEST,109.0,121.0,100.0,111.0,200000,2018-01-02\n"
+	s, err := SniffSchema([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Columns.Date != 6 || s.DateLayout != "2006-01-02" || s.Skip != 0 || !s.Header {
+		t.Errorf("date column %d layout %q skip %d header %v", s.Columns.Date, s.DateLayout, s.Skip, s.Header)
+	}
+	qd, _, err := LoadWithSchema(strings.NewReader(data), "TEST", s)
+	if err != nil || len(qd.Dates) != 2 || qd.Closes[1] != 111 {
+		t.Errorf("loaded %v, %v", qd, err)
+	}
 }
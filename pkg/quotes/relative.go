@@ -0,0 +1,274 @@
+package quotes
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Benchmarks assigns each symbol the index it is judged against, such as
+// its sector index, falling back to a default like NIFTY 50
+type Benchmarks struct {
+	Default  *QuoteData
+	bySymbol map[string]*QuoteData
+}
+
+// Set makes benchmark the index of the symbols
+func (b *Benchmarks) Set(benchmark *QuoteData, symbols ...string) {
+	if b.bySymbol == nil {
+		b.bySymbol = make(map[string]*QuoteData)
+	}
+	for _, s := range symbols {
+		b.bySymbol[s] = benchmark
+	}
+}
+
+// For returns the benchmark of a symbol, nil when there is none
+func (b *Benchmarks) For(symbol string) *QuoteData {
+	if bm, ok := b.bySymbol[symbol]; ok {
+		return bm
+	}
+	return b.Default
+}
+
+// Relative returns the strength of the quotes against their benchmark, nil
+// when the symbol has no benchmark
+func (b *Benchmarks) Relative(q *QuoteData, p RSPeriods) *Relative {
+	bm := b.For(q.Symbol)
+	if bm == nil {
+		return nil
+	}
+	return NewRelative(q, bm, p)
+}
+
+// RSPeriods are the sessions over which relative strength is averaged
+type RSPeriods struct {
+	Mansfield int // moving average of the ratio
+	RRG       int // normalisation window of RS-Ratio and RS-Momentum
+}
+
+// DailyRSPeriods suit daily quotes: a 52 week Mansfield average and a
+// quarter for the rotation graph. Weekly quotes usually take 52 and 14.
+var DailyRSPeriods = RSPeriods{Mansfield: 250, RRG: 63}
+
+// Quadrant is the quadrant of a relative rotation graph
+type Quadrant int
+
+const (
+	Leading   Quadrant = iota + 1 // RS-Ratio and RS-Momentum above 100
+	Weakening                     // RS-Ratio above 100, RS-Momentum below
+	Lagging                       // both below 100
+	Improving                     // RS-Ratio below 100, RS-Momentum above
+)
+
+func (q Quadrant) String() string {
+	switch q {
+	case Leading:
+		return "leading"
+	case Weakening:
+		return "weakening"
+	case Lagging:
+		return "lagging"
+	case Improving:
+		return "improving"
+	}
+	return ""
+}
+
+// Relative is the strength of a symbol against a benchmark. It holds one
+// value per session of the symbol, so index i is the i'th session of the
+// quotes it was built from. Values are NaN until there is enough history.
+type Relative struct {
+	Symbol     string
+	Benchmark  string
+	Dates      []time.Time
+	Ratio      []float64 // 100 * close / benchmark close
+	Mansfield  []float64 // percent the ratio is above its moving average
+	RSRatio    []float64 // trend of the ratio, normalised around 100
+	RSMomentum []float64 // rate of change of RSRatio, normalised around 100
+}
+
+// NewRelative works out the strength of the quotes against a benchmark. On
+// a day the benchmark has no session its previous close is used.
+//
+// RS-Ratio and RS-Momentum follow the usual open approximation of the
+// relative rotation graph: RS-Ratio is 100 plus the z-score of the ratio
+// over the RRG window and RS-Momentum is 100 plus the z-score of the
+// session to session change of RS-Ratio.
+func NewRelative(q *QuoteData, benchmark *QuoteData, p RSPeriods) *Relative {
+	n := len(q.Dates)
+	r := &Relative{
+		Symbol:    q.Symbol,
+		Benchmark: benchmark.Symbol,
+		Dates:     q.Dates,
+		Ratio:     nanSeries(n),
+		Mansfield: nanSeries(n),
+	}
+	for i, d := range q.Dates {
+		k := benchmark.Index(d, Previous)
+		if k < 0 || benchmark.Closes[k] <= 0 || q.Closes[i] <= 0 {
+			continue
+		}
+		r.Ratio[i] = 100 * q.Closes[i] / benchmark.Closes[k]
+	}
+	avg := rollingMean(r.Ratio, p.Mansfield)
+	for i := range r.Ratio {
+		r.Mansfield[i] = (r.Ratio[i]/avg[i] - 1) * 100
+	}
+	r.RSRatio = zscore(r.Ratio, p.RRG)
+	change := nanSeries(n)
+	for i := 1; i < n; i++ {
+		change[i] = (r.RSRatio[i]/r.RSRatio[i-1] - 1) * 100
+	}
+	r.RSMomentum = zscore(change, p.RRG)
+	return r
+}
+
+// Quadrant returns the rotation graph quadrant of the i'th session, 0
+// while RS-Ratio or RS-Momentum is unknown
+func (r *Relative) Quadrant(i int) Quadrant {
+	ratio, momentum := r.RSRatio[i], r.RSMomentum[i]
+	switch {
+	case math.IsNaN(ratio) || math.IsNaN(momentum):
+		return 0
+	case ratio >= 100 && momentum >= 100:
+		return Leading
+	case ratio >= 100:
+		return Weakening
+	case momentum < 100:
+		return Lagging
+	}
+	return Improving
+}
+
+// RSRanks ranks the symbols by RankRS on any date, working each date out once
+type RSRanks struct {
+	rels  []*Relative
+	dates map[time.Time]map[string]float64
+}
+
+// NewRSRanks returns the ranks of the symbols of the relative strengths
+func NewRSRanks(rels []*Relative) *RSRanks {
+	return &RSRanks{rels: rels, dates: make(map[time.Time]map[string]float64)}
+}
+
+// Rank returns the rank of the symbol on the calendar day of the date, false
+// when it has none
+func (r *RSRanks) Rank(symbol string, date time.Time) (float64, bool) {
+	day := truncateDay(date)
+	ranks, ok := r.dates[day]
+	if !ok {
+		ranks = RankRS(r.rels, day)
+		r.dates[day] = ranks
+	}
+	rank, ok := ranks[symbol]
+	return rank, ok
+}
+
+// RankRS ranks the symbols by their Mansfield relative strength on the last
+// session of any of them on or before the date, from 1 for the weakest to 99
+// for the strongest. Symbols without a value on that session, such as those
+// delisted before it, are left out.
+func RankRS(rels []*Relative, date time.Time) map[string]float64 {
+	type score struct {
+		symbol string
+		value  float64
+	}
+	var session time.Time
+	for _, r := range rels {
+		q := QuoteData{Dates: r.Dates}
+		if i := q.Index(date, Previous); i >= 0 && r.Dates[i].After(session) {
+			session = r.Dates[i]
+		}
+	}
+	var scores []score
+	for _, r := range rels {
+		q := QuoteData{Dates: r.Dates}
+		i := q.Index(session, Exact)
+		if session.IsZero() || i < 0 || math.IsNaN(r.Mansfield[i]) {
+			continue
+		}
+		scores = append(scores, score{r.Symbol, r.Mansfield[i]})
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].value < scores[j].value })
+	ranks := make(map[string]float64, len(scores))
+	for k, s := range scores {
+		if len(scores) == 1 {
+			ranks[s.symbol] = 99
+		} else {
+			ranks[s.symbol] = math.Round(1 + 98*float64(k)/float64(len(scores)-1))
+		}
+	}
+	return ranks
+}
+
+// WriteRelative writes the relative strength of the symbols as CSV, one
+// record per symbol and session
+func WriteRelative(w io.Writer, rels ...*Relative) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"Date", "Symbol", "Benchmark", "Ratio", "Mansfield", "RS-Ratio", "RS-Momentum", "Quadrant"})
+	number := func(v float64) string {
+		if math.IsNaN(v) {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', 4, 64)
+	}
+	for _, r := range rels {
+		for i, d := range r.Dates {
+			writer.Write([]string{d.Format("2006-01-02"), r.Symbol, r.Benchmark, number(r.Ratio[i]),
+				number(r.Mansfield[i]), number(r.RSRatio[i]), number(r.RSMomentum[i]), r.Quadrant(i).String()})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// rollingMean is the mean of the last n values, NaN when any of them is
+func rollingMean(values []float64, n int) []float64 {
+	mean := nanSeries(len(values))
+	if n <= 0 {
+		return mean
+	}
+	sum, valid := 0.0, 0
+	for i, v := range values {
+		if math.IsNaN(v) {
+			sum, valid = 0, 0
+			continue
+		}
+		sum += v
+		valid++
+		if valid > n {
+			sum -= values[i-n]
+			valid = n
+		}
+		if valid == n {
+			mean[i] = sum / float64(n)
+		}
+	}
+	return mean
+}
+
+// zscore is 100 plus the number of standard deviations each value is from
+// the mean of the last n values
+func zscore(values []float64, n int) []float64 {
+	z := nanSeries(len(values))
+	mean := rollingMean(values, n)
+	for i := range values {
+		if math.IsNaN(mean[i]) {
+			continue
+		}
+		variance := 0.0
+		for _, v := range values[i-n+1 : i+1] {
+			variance += (v - mean[i]) * (v - mean[i])
+		}
+		if sd := math.Sqrt(variance / float64(n)); sd > 0 {
+			z[i] = 100 + (values[i]-mean[i])/sd
+		} else {
+			z[i] = 100
+		}
+	}
+	return z
+}
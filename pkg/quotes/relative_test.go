@@ -0,0 +1,132 @@
+package quotes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"testing"
+	"time"
+)
+
+// rising returns 80 sessions of the symbol that gain 1% a session for 40
+// sessions and lose 1% a session after, against a flat benchmark at 100
+func rising(symbol string) (*QuoteData, *QuoteData) {
+	closes, flat := make([]float64, 80), make([]float64, 80)
+	for i := range closes {
+		flat[i] = 100
+		if i < 40 {
+			closes[i] = 100 * math.Pow(1.01, float64(i))
+		} else {
+			closes[i] = closes[39] * math.Pow(0.99, float64(i-39))
+		}
+	}
+	g := &Generator{Symbol: symbol, Start: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Seed: 1}
+	q := g.FromCloses(closes)
+	g.Symbol = "BENCH"
+	return q, g.FromCloses(flat)
+}
+
+func TestRelative(t *testing.T) {
+	q, bench := rising("TEST")
+	// the benchmark has no session on the sixth day
+	keep := []int{}
+	for i := range bench.Dates {
+		if i != 5 {
+			keep = append(keep, i)
+		}
+	}
+	bench = bench.pick(keep)
+	bench.Closes[4] = 50
+	r := NewRelative(q, bench, RSPeriods{Mansfield: 10, RRG: 10})
+	if r.Symbol != "TEST" || r.Benchmark != "BENCH" || len(r.Ratio) != len(q.Dates) {
+		t.Fatalf("relative of %s against %s, %d ratios", r.Symbol, r.Benchmark, len(r.Ratio))
+	}
+	if !near(r.Ratio[5], 100*q.Closes[5]/50) || !near(r.Ratio[6], q.Closes[6]) {
+		t.Errorf("ratios %v %v, want the previous benchmark close on the missing day", r.Ratio[5], r.Ratio[6])
+	}
+
+	// warm-up: the averages need 10 ratios, the momentum 10 changes of RS-Ratio
+	for _, c := range []struct {
+		name   string
+		values []float64
+		first  int
+	}{
+		{"mansfield", r.Mansfield, 9}, {"rs-ratio", r.RSRatio, 9}, {"rs-momentum", r.RSMomentum, 19},
+	} {
+		if !math.IsNaN(c.values[c.first-1]) || math.IsNaN(c.values[c.first]) {
+			t.Errorf("%s: %v at %d, %v at %d", c.name, c.values[c.first-1], c.first-1, c.values[c.first], c.first)
+		}
+	}
+
+	// the strength slows before the turn, and the symbol lags and then
+	// improves as its losses slow
+	for i, want := range map[int]Quadrant{18: 0, 38: Weakening, 42: Lagging, 45: Improving} {
+		if got := r.Quadrant(i); got != want {
+			t.Errorf("quadrant at %d: %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestQuadrant(t *testing.T) {
+	nan := math.NaN()
+	r := &Relative{
+		RSRatio:    []float64{nan, 100, 101, 101, 99, 99},
+		RSMomentum: []float64{100, nan, 100, 99, 99, 101},
+	}
+	for i, want := range []Quadrant{0, 0, Leading, Weakening, Lagging, Improving} {
+		if got := r.Quadrant(i); got != want {
+			t.Errorf("quadrant %d: %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestRankRS(t *testing.T) {
+	day := time.Date(2018, 1, 2, 0, 0, 0, 0, time.UTC)
+	rel := func(symbol string, mansfield ...float64) *Relative {
+		return &Relative{Symbol: symbol, Dates: []time.Time{day, day.AddDate(0, 0, 1)}, Mansfield: mansfield}
+	}
+	rels := []*Relative{rel("A", 5, 5), rel("B", -3, 9), rel("C", 1, 1), rel("D", math.NaN(), 2)}
+	ranks := RankRS(rels, day)
+	if len(ranks) != 3 || ranks["B"] != 1 || ranks["C"] != 50 || ranks["A"] != 99 {
+		t.Errorf("ranks %v", ranks)
+	}
+	// C, D, A and B on the next day, whatever its time
+	if rank, ok := NewRSRanks(rels).Rank("D", day.Add(26*time.Hour)); !ok || rank != 34 {
+		t.Errorf("rank of D %v, %v", rank, ok)
+	}
+	// E stopped trading the day before: ranked then, not since
+	stale := &Relative{Symbol: "E", Dates: []time.Time{day.AddDate(0, 0, -1)}, Mansfield: []float64{100}}
+	rels = append(rels, stale)
+	if ranks := RankRS(rels, day.AddDate(0, 0, 5)); len(ranks) != 4 || ranks["B"] != 99 {
+		t.Errorf("ranks with a stale symbol %v", ranks)
+	}
+	if ranks := RankRS([]*Relative{stale}, day); len(ranks) != 1 || ranks["E"] != 99 {
+		t.Errorf("ranks of a stale symbol on its own %v", ranks)
+	}
+	if ranks := RankRS(rels, day.AddDate(0, 0, -1)); len(ranks) != 1 || ranks["E"] != 99 {
+		t.Errorf("ranks before the others trade %v", ranks)
+	}
+}
+
+func TestWriteRelative(t *testing.T) {
+	q, bench := rising("TEST")
+	r := NewRelative(q, bench, RSPeriods{Mansfield: 10, RRG: 10})
+	var buf bytes.Buffer
+	if err := WriteRelative(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(q.Dates)+1 || records[0][0] != "Date" || records[0][7] != "Quadrant" {
+		t.Fatalf("%d records, header %v", len(records), records[0])
+	}
+	if first := records[1]; first[0] != "2018-01-01" || first[1] != "TEST" || first[2] != "BENCH" ||
+		first[3] != "100.0000" || first[4] != "" || first[7] != "" {
+		t.Errorf("first record %v", first)
+	}
+	if last := records[39]; last[7] != "weakening" || last[5] == "" {
+		t.Errorf("record of session 38 %v", last)
+	}
+}
@@ -0,0 +1,74 @@
+package quotes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LineIssue describes a problem found on a single line of a quote file
+type LineIssue struct {
+	Line   int
+	Field  string
+	Reason string
+}
+
+func (l LineIssue) String() string {
+	if l.Field == "" {
+		return fmt.Sprintf("%d: %s", l.Line, l.Reason)
+	}
+	return fmt.Sprintf("%d: (%s) %s", l.Line, l.Field, l.Reason)
+}
+
+// LoadReport lists what happened to every row while loading a quote file
+type LoadReport struct {
+	Symbol   string
+	Source   string
+	Parsed   int
+	Cached   bool // loaded from the binary cache; the rows are those of the load that built it
+	Skipped  []LineIssue
+	Repaired []LineIssue
+}
+
+func newLoadReport(symbol string, source string) *LoadReport {
+	return &LoadReport{
+		Symbol: symbol,
+		Source: source,
+	}
+}
+
+func (r *LoadReport) skip(line int, field string, reason string) {
+	r.Skipped = append(r.Skipped, LineIssue{Line: line, Field: field, Reason: reason})
+}
+
+func (r *LoadReport) repair(line int, field string, reason string) {
+	r.Repaired = append(r.Repaired, LineIssue{Line: line, Field: field, Reason: reason})
+}
+
+// Clean returns true when every row was parsed without being skipped or repaired
+func (r *LoadReport) Clean() bool {
+	return len(r.Skipped) == 0 && len(r.Repaired) == 0
+}
+
+func (r *LoadReport) String() string {
+	if r.Cached {
+		return fmt.Sprintf("%s: cached:%d skipped:%d repaired:%d",
+			r.Symbol, r.Parsed, len(r.Skipped), len(r.Repaired))
+	}
+	return fmt.Sprintf("%s: parsed:%d skipped:%d repaired:%d",
+		r.Symbol, r.Parsed, len(r.Skipped), len(r.Repaired))
+}
+
+// Details returns the summary followed by one line per skipped or repaired row
+func (r *LoadReport) Details() string {
+	var b strings.Builder
+	b.WriteString(r.String())
+	for _, l := range r.Skipped {
+		b.WriteString("\n  skipped ")
+		b.WriteString(l.String())
+	}
+	for _, l := range r.Repaired {
+		b.WriteString("\n  repaired ")
+		b.WriteString(l.String())
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package quotes
+
+import (
+	"time"
+)
+
+type Period int
+
+const (
+	Weekly Period = iota + 1
+	Monthly
+	Quarterly
+	Yearly
+)
+
+func (p Period) String() string {
+	switch p {
+	case Weekly:
+		return "weekly"
+	case Monthly:
+		return "monthly"
+	case Quarterly:
+		return "quarterly"
+	case Yearly:
+		return "yearly"
+	}
+	return "unknown"
+}
+
+// key identifies the calendar period a date falls in
+func (p Period) key(d time.Time) int {
+	switch p {
+	case Weekly:
+		y, w := d.ISOWeek()
+		return y*100 + w
+	case Monthly:
+		return d.Year()*100 + int(d.Month())
+	case Quarterly:
+		return d.Year()*100 + (int(d.Month())-1)/3
+	default:
+		return d.Year()
+	}
+}
+
+// Resample aggregates daily quotes into calendar periods: the open of the
+// first session, the highest high, the lowest low, the close of the last
+// session and the total volume. Each bar carries the date of the last
+// session of its period, so partial periods at either end and weeks with
+// holidays are kept as they are. Sessions without prices (a null row
+// loaded as zeros) are left out. Optional series are aggregated too.
+func (q *QuoteData) Resample(p Period) *QuoteData {
+	r := &QuoteData{Symbol: q.Symbol}
+	hasAdj := len(q.AdjCloses) == len(q.Closes)
+	current, period := -1, -1
+	for i, d := range q.Dates {
+		if q.Highs[i] <= 0 {
+			continue
+		}
+		if k := p.key(d); current == -1 || k != period {
+			period = k
+			r.Dates = append(r.Dates, d)
+			r.Opens = append(r.Opens, q.Opens[i])
+			r.Highs = append(r.Highs, q.Highs[i])
+			r.Lows = append(r.Lows, q.Lows[i])
+			r.Closes = append(r.Closes, q.Closes[i])
+			r.AdjCloses = append(r.AdjCloses, q.Closes[i])
+			r.Volumes = append(r.Volumes, q.Volumes[i])
+			r.appendExtras(q, i)
+			current++
+		} else {
+			r.Dates[current] = d
+			if q.Highs[i] > r.Highs[current] {
+				r.Highs[current] = q.Highs[i]
+			}
+			if q.Lows[i] > 0 && (q.Lows[i] < r.Lows[current] || r.Lows[current] <= 0) {
+				r.Lows[current] = q.Lows[i]
+			}
+			r.Closes[current] = q.Closes[i]
+			r.Volumes[current] += q.Volumes[i]
+			r.addExtras(current, q, i)
+		}
+		if hasAdj {
+			r.AdjCloses[current] = q.AdjCloses[i]
+		} else {
+			r.AdjCloses[current] = q.Closes[i]
+		}
+	}
+	r.finishExtras()
+	return r
+}
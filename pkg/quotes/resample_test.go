@@ -0,0 +1,54 @@
+package quotes
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResample(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	bar := func(d time.Time, o, h, l, c, v float64) Quote {
+		return Quote{Date: d, Open: o, High: h, Low: l, Close: c, AdjClose: c, Volume: v}
+	}
+	// from a Wednesday to a Tuesday, over the New Year holiday and a null
+	// row; Monday the 31st of December is in the first ISO week of 2019
+	q := convert([]Quote{
+		bar(day(2018, 12, 26), 100, 102, 99, 101, 10),
+		bar(day(2018, 12, 27), 101, 105, 100, 104, 20),
+		bar(day(2018, 12, 28), 104, 104, 97, 98, 30),
+		bar(day(2018, 12, 31), 98, 99, 95, 96, 40),
+		bar(day(2019, 1, 2), 96, 110, 96, 108, 50),
+		bar(day(2019, 1, 3), 0, 0, 0, 0, 0),
+		bar(day(2019, 1, 4), 108, 109, 103, 105, 60),
+		bar(day(2019, 1, 7), 105, 106, 101, 102, 70),
+		bar(day(2019, 1, 8), 102, 107, 102, 106, 80),
+	}, "TEST")
+	years := []Quote{
+		bar(day(2018, 12, 31), 100, 105, 95, 96, 100),
+		bar(day(2019, 1, 8), 96, 110, 96, 106, 260),
+	}
+	for _, c := range []struct {
+		period Period
+		want   []Quote
+	}{
+		{Weekly, []Quote{
+			bar(day(2018, 12, 28), 100, 105, 97, 98, 60),
+			bar(day(2019, 1, 4), 98, 110, 95, 105, 150),
+			bar(day(2019, 1, 8), 105, 107, 101, 106, 150),
+		}},
+		{Monthly, years},
+		{Quarterly, years},
+		{Yearly, years},
+	} {
+		r := q.Resample(c.period)
+		if len(r.Dates) != len(c.want) {
+			t.Errorf("%s: %d bars %v, want %d", c.period, len(r.Dates), r.Dates, len(c.want))
+			continue
+		}
+		for i, want := range c.want {
+			if got := r.Quote(i); got != want {
+				t.Errorf("%s bar %d: %+v, want %+v", c.period, i, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,410 @@
+package quotes
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Columns holds the index of each quote field in a record; -1 when absent
+type Columns struct {
+	Date     int
+	Open     int
+	High     int
+	Low      int
+	Close    int
+	AdjClose int
+	Volume   int
+	// optional series
+	Delivery     int
+	DeliveryPct  int
+	Turnover     int
+	OpenInterest int
+}
+
+// column returns the index of an optional series
+func (c Columns) column(s Series) int {
+	switch s {
+	case Delivery:
+		return c.Delivery
+	case DeliveryPct:
+		return c.DeliveryPct
+	case Turnover:
+		return c.Turnover
+	case OpenInterest:
+		return c.OpenInterest
+	}
+	return -1
+}
+
+// set sets the index of an optional series
+func (c *Columns) set(s Series, i int) {
+	switch s {
+	case Delivery:
+		c.Delivery = i
+	case DeliveryPct:
+		c.DeliveryPct = i
+	case Turnover:
+		c.Turnover = i
+	case OpenInterest:
+		c.OpenInterest = i
+	}
+}
+
+// extras returns the optional series present in the record
+func (c Columns) extras() []Series {
+	var extras []Series
+	for _, s := range AllSeries {
+		if c.column(s) >= 0 {
+			extras = append(extras, s)
+		}
+	}
+	return extras
+}
+
+// Schema describes the shape of a quote file
+type Schema struct {
+	Delimiter  rune
+	Comment    string     // records starting with this prefix are ignored
+	Skip       int        // records to ignore before the header or the first quote
+	Header     bool       // the record after Skip holds the column names
+	HeaderLine []string   // the header as found in the file
+	Preamble   [][]string // the records before the header or the first quote
+	DateLayout string
+	Location   *time.Location // time zone of dates without one; UTC when nil
+	Columns    Columns
+	Nulls      []string // markers of a missing value
+	Precision  int      // decimals written for prices; as many as needed when -1
+	CRLF       bool     // lines end with \r\n
+}
+
+// DefaultColumns is the column order of the Yahoo downloads in data/
+var DefaultColumns = Columns{Date: 0, Open: 1, High: 2, Low: 3, Close: 4, AdjClose: 5, Volume: 6,
+	Delivery: -1, DeliveryPct: -1, Turnover: -1, OpenInterest: -1}
+
+// DateLayouts are the date formats tried when sniffing a quote file
+var DateLayouts = []string{
+	"02-01-2006",
+	"2006-01-02",
+	"02/01/2006",
+	"2006/01/02",
+	"02-Jan-2006",
+	"02 Jan 2006",
+	"20060102",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"02-01-2006 15:04:05",
+	"02-01-2006 15:04",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+var columnNames = map[string]string{
+	"date":                   "Date",
+	"timestamp":              "Date",
+	"datetime":               "Date",
+	"open":                   "Open",
+	"high":                   "High",
+	"low":                    "Low",
+	"close":                  "Close",
+	"adj close":              "AdjClose",
+	"adjclose":               "AdjClose",
+	"adj_close":              "AdjClose",
+	"volume":                 "Volume",
+	"shares traded":          "Volume",
+	"total traded quantity":  "Volume",
+	"deliverable qty":        "Delivery",
+	"deliverable quantity":   "Delivery",
+	"delivery":               "Delivery",
+	"deliv_qty":              "Delivery",
+	"delivery %":             "DeliveryPct",
+	"% dly qt to traded qty": "DeliveryPct",
+	"deliv_per":              "DeliveryPct",
+	"turnover":               "Turnover",
+	"open interest":          "OpenInterest",
+	"openinterest":           "OpenInterest",
+	"oi":                     "OpenInterest",
+}
+
+// DefaultSchema returns the schema the loader has always assumed: Yahoo
+// column order, dd-mm-yyyy dates and skip leading records.
+func DefaultSchema(skip int) *Schema {
+	return &Schema{
+		Delimiter:  ',',
+		Skip:       skip,
+		DateLayout: "02-01-2006",
+		Columns:    DefaultColumns,
+		Nulls:      []string{"null"},
+		Precision:  -1,
+	}
+}
+
+// NewReader returns a csv reader configured for the schema
+func (s *Schema) NewReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.Comma = s.Delimiter
+	reader.ReuseRecord = true
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader
+}
+
+// BindHeader sets the columns from a header record. Unknown names are ignored.
+func (s *Schema) BindHeader(header []string) error {
+	c := Columns{Date: -1, Open: -1, High: -1, Low: -1, Close: -1, AdjClose: -1, Volume: -1,
+		Delivery: -1, DeliveryPct: -1, Turnover: -1, OpenInterest: -1}
+	for i, h := range header {
+		switch columnName(h) {
+		case "Date":
+			c.Date = i
+		case "Open":
+			c.Open = i
+		case "High":
+			c.High = i
+		case "Low":
+			c.Low = i
+		case "Close":
+			c.Close = i
+		case "AdjClose":
+			c.AdjClose = i
+		case "Volume":
+			c.Volume = i
+		case "Delivery":
+			c.Delivery = i
+		case "DeliveryPct":
+			c.DeliveryPct = i
+		case "Turnover":
+			c.Turnover = i
+		case "OpenInterest":
+			c.OpenInterest = i
+		}
+	}
+	if c.Date < 0 || c.Open < 0 || c.High < 0 || c.Low < 0 || c.Close < 0 {
+		return fmt.Errorf("header %v lacks one of Date, Open, High, Low, Close", header)
+	}
+	s.Columns = c
+	s.HeaderLine = append([]string(nil), header...)
+	return nil
+}
+
+// AddColumns appends a column for each of the optional series the schema
+// lacks, so that writing quotes holding them keeps their values
+func (s *Schema) AddColumns(series ...Series) {
+	for _, e := range series {
+		if s.Columns.column(e) >= 0 {
+			continue
+		}
+		if s.HeaderLine != nil {
+			s.HeaderLine = append(s.HeaderLine, seriesHeader[e])
+		}
+		s.Columns.set(e, s.width())
+	}
+}
+
+// columnName maps a header field such as "# Date" or "Adj Close" to a quote field
+func columnName(field string) string {
+	return columnNames[strings.ToLower(strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(field), "#")))]
+}
+
+// parseDate parses a date of the schema's layout in its time zone
+func (s *Schema) parseDate(field string) (time.Time, error) {
+	if s.Location == nil {
+		return time.Parse(s.DateLayout, field)
+	}
+	return time.ParseInLocation(s.DateLayout, field, s.Location)
+}
+
+func (s *Schema) isNull(field string) bool {
+	for _, n := range s.Nulls {
+		if field == n {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) isComment(record []string) bool {
+	return s.Comment != "" && len(record) > 0 && strings.HasPrefix(strings.TrimSpace(record[0]), s.Comment)
+}
+
+// width is the number of fields a record needs to hold every column
+func (s *Schema) width() int {
+	c := s.Columns
+	w := 0
+	for _, i := range []int{c.Date, c.Open, c.High, c.Low, c.Close, c.AdjClose, c.Volume,
+		c.Delivery, c.DeliveryPct, c.Turnover, c.OpenInterest} {
+		if i+1 > w {
+			w = i + 1
+		}
+	}
+	return w
+}
+
+const sniffSize = 8192
+
+// SniffFile works out the schema of a quote file from its start
+func SniffFile(file string) (*Schema, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sample := make([]byte, sniffSize)
+	n, err := io.ReadFull(f, sample)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	return SniffSchema(sample[:n])
+}
+
+// SniffSchema works out the schema from the start of a quote file: the
+// delimiter, the records before the data, the header and the date layout.
+func SniffSchema(sample []byte) (*Schema, error) {
+	s := DefaultSchema(0)
+	s.Comment = "#"
+	s.Delimiter = sniffDelimiter(sample)
+	s.CRLF = bytes.Contains(sample, []byte("\r\n"))
+
+	// the last line of a truncated sample may be cut short
+	if i := bytes.LastIndexByte(sample, '\n'); i >= 0 && i < len(sample)-1 {
+		sample = sample[:i+1]
+	}
+	reader := s.NewReader(bytes.NewReader(sample))
+	reader.ReuseRecord = false
+	var records [][]string
+	for len(records) < 50 {
+		record, err := reader.Read()
+		if err != nil {
+			break
+		}
+		records = append(records, record)
+	}
+
+	first := -1
+	for i, record := range records {
+		if looksLikeHeader(record) {
+			s.Skip = i
+			s.Header = true
+			if err := s.BindHeader(record); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		date := s.Columns.Date
+		if date >= len(record) {
+			continue
+		}
+		if layout := sniffDateLayout(strings.TrimSpace(record[date])); layout != "" && isNumeric(without(record, date)) {
+			if !s.Header {
+				s.Skip = i
+			}
+			first = i
+			break
+		}
+	}
+	if first == -1 {
+		return nil, errors.New("no quotes found in the sample")
+	}
+	s.Preamble = records[:s.Skip]
+
+	var dates []string
+	for _, record := range records[first:] {
+		if s.Columns.Date < len(record) && !s.isComment(record) {
+			dates = append(dates, strings.TrimSpace(record[s.Columns.Date]))
+		}
+	}
+	s.DateLayout = commonDateLayout(dates)
+	if s.DateLayout == "" {
+		return nil, fmt.Errorf("unknown date format %q", dates[0])
+	}
+	for _, record := range records[first:] {
+		if s.Columns.Close < len(record) {
+			close := strings.TrimSpace(record[s.Columns.Close])
+			if i := strings.IndexByte(close, '.'); i >= 0 && len(close)-i-1 > s.Precision {
+				s.Precision = len(close) - i - 1
+			}
+		}
+	}
+	if strings.Contains(s.DateLayout, "15:04") {
+		s.Location = IST
+	}
+	return s, nil
+}
+
+func sniffDelimiter(sample []byte) rune {
+	best, bestLines := ',', 0
+	for _, d := range []rune{',', ';', '\t', '|'} {
+		lines := 0
+		for _, l := range strings.Split(string(sample), "\n") {
+			if strings.Count(l, string(d)) >= 4 {
+				lines++
+			}
+		}
+		if lines > bestLines {
+			best, bestLines = d, lines
+		}
+	}
+	return best
+}
+
+func looksLikeHeader(record []string) bool {
+	date, close := false, false
+	for _, f := range record {
+		switch columnName(f) {
+		case "Date":
+			date = true
+		case "Close":
+			close = true
+		}
+	}
+	return date && close
+}
+
+func isNumeric(fields []string) bool {
+	numbers := 0
+	for _, f := range fields {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(f), 64); err == nil {
+			numbers++
+		}
+	}
+	return numbers >= 4
+}
+
+// without returns the fields of a record but the one at index i
+func without(record []string, i int) []string {
+	fields := append([]string(nil), record[:i]...)
+	return append(fields, record[i+1:]...)
+}
+
+func sniffDateLayout(field string) string {
+	for _, layout := range DateLayouts {
+		if _, err := time.Parse(layout, field); err == nil {
+			return layout
+		}
+	}
+	return ""
+}
+
+// commonDateLayout returns the first layout that parses every date
+func commonDateLayout(dates []string) string {
+	for _, layout := range DateLayouts {
+		ok := true
+		for _, d := range dates {
+			if _, err := time.Parse(layout, d); err != nil {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return layout
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package quotes
+
+// Series names an optional series of QuoteData. Quotes hold a series only
+// when their source has it; missing values within a series are 0.
+type Series int
+
+const (
+	Delivery     Series = iota + 1 // deliverable quantity
+	DeliveryPct                    // deliverable quantity as a percentage of volume
+	Turnover                       // traded value
+	OpenInterest                   // open futures and options contracts
+)
+
+// AllSeries lists the optional series
+var AllSeries = []Series{Delivery, DeliveryPct, Turnover, OpenInterest}
+
+func (s Series) String() string {
+	switch s {
+	case Delivery:
+		return "delivery"
+	case DeliveryPct:
+		return "delivery %"
+	case Turnover:
+		return "turnover"
+	case OpenInterest:
+		return "open interest"
+	}
+	return "unknown"
+}
+
+// Series returns the values of an optional series, nil when the quotes do not have it
+func (q *QuoteData) Series(s Series) []float64 {
+	if p := q.series(s); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// SetSeries sets the values of an optional series, one per session
+func (q *QuoteData) SetSeries(s Series, values []float64) {
+	if p := q.series(s); p != nil {
+		*p = values
+	}
+}
+
+// Has returns true when the quotes hold the series
+func (q *QuoteData) Has(s Series) bool {
+	return q.Series(s) != nil
+}
+
+// Extras returns the optional series the quotes hold
+func (q *QuoteData) Extras() []Series {
+	var extras []Series
+	for _, s := range AllSeries {
+		if q.Has(s) {
+			extras = append(extras, s)
+		}
+	}
+	return extras
+}
+
+func (q *QuoteData) series(s Series) *[]float64 {
+	switch s {
+	case Delivery:
+		return &q.Deliveries
+	case DeliveryPct:
+		return &q.DeliveryPcts
+	case Turnover:
+		return &q.Turnovers
+	case OpenInterest:
+		return &q.OpenInterests
+	}
+	return nil
+}
+
+func (q *Quote) value(s Series) *float64 {
+	switch s {
+	case Delivery:
+		return &q.Delivery
+	case DeliveryPct:
+		return &q.DeliveryPct
+	case Turnover:
+		return &q.Turnover
+	case OpenInterest:
+		return &q.OpenInterest
+	}
+	return nil
+}
+
+// deriveDeliveryPct works out the delivery percentage of quotes that have
+// the deliverable quantity but not the percentage
+func (q *QuoteData) deriveDeliveryPct() {
+	if q.Deliveries == nil || q.DeliveryPcts != nil {
+		return
+	}
+	q.DeliveryPcts = make([]float64, len(q.Deliveries))
+	for i, d := range q.Deliveries {
+		if q.Volumes[i] > 0 {
+			q.DeliveryPcts[i] = d / q.Volumes[i] * 100
+		}
+	}
+}
+
+// The extras of a bar aggregating several sessions, as built by Resample
+// and Daily: quantities and turnover are summed, open interest is that of
+// the last session and the delivery percentage is weighted by volume. While
+// a bar is built its DeliveryPcts hold the deliverable quantity.
+
+// appendExtras starts a bar of r with the extras of session i of q
+func (r *QuoteData) appendExtras(q *QuoteData, i int) {
+	for _, s := range q.Extras() {
+		v := q.Series(s)[i]
+		if s == DeliveryPct {
+			v *= q.Volumes[i] / 100
+		}
+		p := r.series(s)
+		*p = append(*p, v)
+	}
+}
+
+// addExtras adds the extras of session i of q to bar j of r
+func (r *QuoteData) addExtras(j int, q *QuoteData, i int) {
+	for _, s := range q.Extras() {
+		v := q.Series(s)[i]
+		switch s {
+		case OpenInterest:
+			r.OpenInterests[j] = v
+		case DeliveryPct:
+			r.DeliveryPcts[j] += v * q.Volumes[i] / 100
+		default:
+			(*r.series(s))[j] += v
+		}
+	}
+}
+
+// finishExtras turns the deliverable quantity of each bar into a percentage
+func (r *QuoteData) finishExtras() {
+	for j := range r.DeliveryPcts {
+		if r.Volumes[j] > 0 {
+			r.DeliveryPcts[j] = r.DeliveryPcts[j] / r.Volumes[j] * 100
+		} else {
+			r.DeliveryPcts[j] = 0
+		}
+	}
+}
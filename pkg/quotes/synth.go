@@ -0,0 +1,218 @@
+package quotes
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Model produces the next close of a synthetic price series
+type Model interface {
+	Close(r *rand.Rand, prev float64) float64
+}
+
+// resetter is a model that keeps state from session to session, which
+// Generate resets before every run
+type resetter interface {
+	Reset()
+}
+
+const tradingDays = 252
+
+// GBM is geometric Brownian motion with annualised drift and volatility
+type GBM struct {
+	Drift      float64
+	Volatility float64
+}
+
+func (m GBM) Close(r *rand.Rand, prev float64) float64 {
+	dt := 1.0 / tradingDays
+	return prev * math.Exp((m.Drift-m.Volatility*m.Volatility/2)*dt+m.Volatility*math.Sqrt(dt)*r.NormFloat64())
+}
+
+// JumpDiffusion is GBM with jumps: on average JumpsPerYear times a year the
+// price moves by a normally distributed log return of JumpMean and JumpStd
+type JumpDiffusion struct {
+	GBM
+	JumpsPerYear float64
+	JumpMean     float64
+	JumpStd      float64
+}
+
+func (m JumpDiffusion) Close(r *rand.Rand, prev float64) float64 {
+	close := m.GBM.Close(r, prev)
+	if r.Float64() < m.JumpsPerYear/tradingDays {
+		close *= math.Exp(m.JumpMean + m.JumpStd*r.NormFloat64())
+	}
+	return close
+}
+
+// RegimeSwitching alternates between a trending market and a range bound
+// one that reverts to the price at which the range began. Each session it
+// leaves the current regime with probability Switch.
+type RegimeSwitching struct {
+	Trend      GBM
+	Volatility float64 // annualised, of the range regime
+	Reversion  float64 // share of the distance to the range's mean closed each session
+	Switch     float64
+	trending   bool
+	mean       float64
+}
+
+// Reset forgets the regime, as before the first session
+func (m *RegimeSwitching) Reset() {
+	m.trending = false
+	m.mean = 0
+}
+
+func (m *RegimeSwitching) Close(r *rand.Rand, prev float64) float64 {
+	if m.mean == 0 || r.Float64() < m.Switch {
+		m.trending = !m.trending
+		m.mean = prev
+	}
+	if m.trending {
+		return m.Trend.Close(r, prev)
+	}
+	shock := m.Volatility / math.Sqrt(tradingDays) * r.NormFloat64()
+	return prev * math.Exp(m.Reversion*math.Log(m.mean/prev)+shock)
+}
+
+// Generator builds plausible daily quotes around a series of closes
+type Generator struct {
+	Symbol   string
+	Start    time.Time
+	Seed     int64
+	Price    float64         // first close
+	Volume   float64         // mean volume
+	Range    float64         // typical distance of high and low from the body, 0.01 when 0
+	Calendar TradingCalendar // weekdays when nil
+}
+
+// Generate returns n sessions of the model. The same seed gives the same quotes.
+func (g *Generator) Generate(m Model, n int) *QuoteData {
+	r := rand.New(rand.NewSource(g.Seed))
+	if s, ok := m.(resetter); ok {
+		s.Reset()
+	}
+	closes := make([]float64, n)
+	prev := g.Price
+	for i := range closes {
+		if i == 0 {
+			closes[i] = prev
+		} else {
+			closes[i] = m.Close(r, prev)
+		}
+		prev = closes[i]
+	}
+	return g.bars(r, closes)
+}
+
+// FromCloses returns quotes closing at the given prices
+func (g *Generator) FromCloses(closes []float64) *QuoteData {
+	return g.bars(rand.New(rand.NewSource(g.Seed)), closes)
+}
+
+// bars dresses the closes with an open near the previous close, a high and
+// low beyond the body and a log-normal volume. High >= max(open, close) and
+// 0 < low <= min(open, close) always hold.
+func (g *Generator) bars(r *rand.Rand, closes []float64) *QuoteData {
+	n := len(closes)
+	qd := &QuoteData{
+		Symbol:    g.Symbol,
+		Dates:     make([]time.Time, n),
+		Opens:     make([]float64, n),
+		Highs:     make([]float64, n),
+		Lows:      make([]float64, n),
+		Closes:    make([]float64, n),
+		AdjCloses: make([]float64, n),
+		Volumes:   make([]float64, n),
+	}
+	var cal TradingCalendar = weekdays{}
+	if g.Calendar != nil {
+		cal = g.Calendar
+	}
+	spread := g.Range
+	if spread <= 0 {
+		spread = 0.01
+	}
+	volume := g.Volume
+	if volume <= 0 {
+		volume = 100000
+	}
+	day := truncateDay(g.Start)
+	if g.Start.IsZero() {
+		day = time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC)
+	}
+	for i, close := range closes {
+		for !cal.IsTradingDay(day) {
+			day = day.AddDate(0, 0, 1)
+		}
+		qd.Dates[i] = day
+		day = day.AddDate(0, 0, 1)
+
+		open := close
+		if i > 0 {
+			open = closes[i-1] * math.Exp(spread/2*r.NormFloat64())
+		}
+		top, bottom := math.Max(open, close), math.Min(open, close)
+		qd.Opens[i] = open
+		qd.Closes[i] = close
+		qd.AdjCloses[i] = close
+		qd.Highs[i] = top * (1 + spread*math.Abs(r.NormFloat64()))
+		qd.Lows[i] = bottom / (1 + spread*math.Abs(r.NormFloat64()))
+		qd.Volumes[i] = math.Round(volume * math.Exp(0.5*r.NormFloat64()-0.125))
+	}
+	return qd
+}
+
+// CrossoverCloses returns n closes whose fast simple moving average crosses
+// above the slow one exactly at bar at: the price drifts down and then
+// rallies. The fast average is below the slow one on the bar before.
+// at must be at least slow+2 and less than n. It returns nil when the
+// averages never cross, such as with a fast period of 0.
+func CrossoverCloses(fast int, slow int, at int, n int, price float64) []float64 {
+	if at < slow+2 || at >= n || fast >= slow {
+		return nil
+	}
+	// a decline long enough for both averages to settle, then a rally
+	decline := slow + fast
+	path := make([]float64, 0, decline+n)
+	for i := 0; i < decline; i++ {
+		path = append(path, price*(1-0.004*float64(i)))
+	}
+	bottom := path[len(path)-1]
+	for i := 1; len(path) < decline+n; i++ {
+		path = append(path, bottom*(1+0.01*float64(i)))
+	}
+	cross := -1
+	for i := slow; i < len(path); i++ {
+		if sma(path, i, fast) > sma(path, i, slow) && sma(path, i-1, fast) < sma(path, i-1, slow) {
+			cross = i
+			break
+		}
+	}
+	if cross < 0 {
+		return nil
+	}
+	// move the crossover to bar at by adding or removing flat bars at the start
+	shift := at - cross
+	if shift > 0 {
+		flat := make([]float64, shift)
+		for i := range flat {
+			flat[i] = path[0]
+		}
+		path = append(flat, path...)
+	} else {
+		path = path[-shift:]
+	}
+	return path[:n]
+}
+
+// sma is the simple moving average of the period closes ending at i
+func sma(closes []float64, i int, period int) float64 {
+	sum := 0.0
+	for k := i - period + 1; k <= i; k++ {
+		sum += closes[k]
+	}
+	return sum / float64(period)
+}
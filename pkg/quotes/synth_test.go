@@ -0,0 +1,55 @@
+package quotes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGenerate(t *testing.T) {
+	g := &Generator{Symbol: "SYNTH", Start: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Seed: 7, Price: 100}
+	models := []Model{
+		GBM{Drift: 0.1, Volatility: 0.3},
+		JumpDiffusion{GBM: GBM{Volatility: 0.2}, JumpsPerYear: 5, JumpStd: 0.1},
+		&RegimeSwitching{Trend: GBM{Drift: 0.3, Volatility: 0.2}, Volatility: 0.1, Reversion: 0.1, Switch: 0.02},
+	}
+	for _, m := range models {
+		qd := g.Generate(m, 500)
+		if report := (&Validator{MaxJump: 1}).Validate(qd); len(report.Issues) > 0 {
+			t.Errorf("%T: %s", m, report)
+		}
+		for i := range qd.Dates {
+			q := qd.Quote(i)
+			if q.Low <= 0 || q.High < q.Open || q.High < q.Close || q.Low > q.Open || q.Low > q.Close {
+				t.Fatalf("%T: invalid session %v", m, q)
+			}
+		}
+	}
+	for _, m := range models {
+		a := g.Generate(m, 100)
+		b := g.Generate(m, 100)
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("%T: same seed gave different quotes", m)
+		}
+	}
+}
+
+func TestCrossoverCloses(t *testing.T) {
+	for _, at := range []int{52, 60, 150, 299} {
+		closes := CrossoverCloses(20, 50, at, 300, 100)
+		if len(closes) != 300 {
+			t.Fatalf("at %d: got %d closes", at, len(closes))
+		}
+		for i := 50; i < len(closes); i++ {
+			cross := sma(closes, i, 20) > sma(closes, i, 50) && sma(closes, i-1, 20) < sma(closes, i-1, 50)
+			if cross != (i == at) {
+				t.Errorf("at %d: crossover on bar %d is %v", at, i, cross)
+			}
+		}
+	}
+	for _, periods := range [][2]int{{0, 50}, {50, 20}, {20, 20}} {
+		if closes := CrossoverCloses(periods[0], periods[1], 60, 300, 100); closes != nil {
+			t.Errorf("%v: %d closes without a crossover", periods, len(closes))
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package quotes
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TradingCalendar tells which days the exchange was open
+type TradingCalendar interface {
+	IsTradingDay(date time.Time) bool
+}
+
+// coverage is implemented by calendars that know the holidays of some years only
+type coverage interface {
+	Covers(date time.Time) bool
+}
+
+// weekdays is the calendar used when none is given: every Monday to Friday
+type weekdays struct{}
+
+func (weekdays) IsTradingDay(date time.Time) bool {
+	return date.Weekday() != time.Saturday && date.Weekday() != time.Sunday
+}
+
+type IssueKind int
+
+const (
+	NullPrice IssueKind = iota + 1
+	ZeroVolume
+	HighBelowLow
+	OutsideRange
+	DuplicateDate
+	OutOfOrder
+	MissingSessions
+	SuspiciousJump
+)
+
+var issueNames = map[IssueKind]string{
+	NullPrice:       "null or zero price",
+	ZeroVolume:      "zero volume",
+	HighBelowLow:    "high below low",
+	OutsideRange:    "open or close outside high/low",
+	DuplicateDate:   "duplicate date",
+	OutOfOrder:      "date out of order",
+	MissingSessions: "missing sessions",
+	SuspiciousJump:  "suspicious jump",
+}
+
+func (k IssueKind) String() string {
+	if n, ok := issueNames[k]; ok {
+		return n
+	}
+	return "unknown"
+}
+
+// Issue is a data problem found at Index of the validated quotes
+type Issue struct {
+	Index  int
+	Date   time.Time
+	Kind   IssueKind
+	Detail string
+}
+
+func (i Issue) String() string {
+	if i.Detail == "" {
+		return fmt.Sprintf("%s %s", i.Date.Format("2006-01-02"), i.Kind)
+	}
+	return fmt.Sprintf("%s %s: %s", i.Date.Format("2006-01-02"), i.Kind, i.Detail)
+}
+
+// ValidationReport lists the issues found in the quotes of a symbol
+type ValidationReport struct {
+	Symbol   string
+	Sessions int
+	Issues   []Issue
+}
+
+// Count returns the number of issues of a kind
+func (r *ValidationReport) Count(kind IssueKind) int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *ValidationReport) String() string {
+	s := fmt.Sprintf("%s: %d sessions, %d issues", r.Symbol, r.Sessions, len(r.Issues))
+	for k := NullPrice; k <= SuspiciousJump; k++ {
+		if n := r.Count(k); n > 0 {
+			s += fmt.Sprintf(", %s:%d", k, n)
+		}
+	}
+	return s
+}
+
+// Validator checks quotes for data problems
+type Validator struct {
+	Calendar TradingCalendar // weekdays when nil
+	MaxJump  float64         // largest close to close change taken as genuine, 0.3 when 0
+}
+
+type RepairPolicy int
+
+const (
+	// RepairDrop removes the bad sessions
+	RepairDrop RepairPolicy = iota + 1
+	// RepairForwardFill replaces a bad session by the previous close, with no volume
+	RepairForwardFill
+	// RepairInterpolate replaces a bad session by a close interpolated
+	// between its neighbours, with no volume
+	RepairInterpolate
+)
+
+func (v *Validator) calendar() TradingCalendar {
+	if v.Calendar == nil {
+		return weekdays{}
+	}
+	return v.Calendar
+}
+
+func (v *Validator) maxJump() float64 {
+	if v.MaxJump <= 0 {
+		return 0.3
+	}
+	return v.MaxJump
+}
+
+// Validate returns the issues found in the quotes
+func (v *Validator) Validate(q *QuoteData) *ValidationReport {
+	r := &ValidationReport{Symbol: q.Symbol, Sessions: len(q.Dates)}
+	add := func(i int, kind IssueKind, detail string) {
+		r.Issues = append(r.Issues, Issue{Index: i, Date: q.Dates[i], Kind: kind, Detail: detail})
+	}
+	cal := v.calendar()
+	key, layout := truncateDay, "2006-01-02"
+	if q.IsIntraday() {
+		key, layout = sessionTime, "2006-01-02 15:04"
+	}
+	prev := -1 // last session with usable prices
+	for i := range q.Dates {
+		if i > 0 {
+			d, p := key(q.Dates[i]), key(q.Dates[i-1])
+			switch {
+			case d.Equal(p):
+				add(i, DuplicateDate, "")
+			case d.Before(p):
+				add(i, OutOfOrder, fmt.Sprintf("after %s", p.Format(layout)))
+			default:
+				// sessions are missed by the day, not by the bar
+				d, p = truncateDay(d), truncateDay(p)
+				if n := missingSessions(cal, p, d); n > 0 {
+					add(i, MissingSessions, fmt.Sprintf("%d since %s", n, p.Format("2006-01-02"))+unknownHolidays(cal, p, d))
+				}
+			}
+		}
+		if problem, detail := badBar(q, i); problem != 0 {
+			add(i, problem, detail)
+			continue
+		}
+		if q.Volumes[i] <= 0 {
+			add(i, ZeroVolume, "")
+		}
+		if prev >= 0 {
+			change := q.Closes[i]/q.Closes[prev] - 1
+			if math.Abs(change) > v.maxJump() {
+				add(i, SuspiciousJump, jumpDetail(q.Closes[prev], q.Closes[i]))
+			}
+		}
+		prev = i
+	}
+	return r
+}
+
+// badBar returns the problem that makes a session unusable, or 0
+func badBar(q *QuoteData, i int) (IssueKind, string) {
+	o, h, l, c := q.Opens[i], q.Highs[i], q.Lows[i], q.Closes[i]
+	switch {
+	case o <= 0 || h <= 0 || l <= 0 || c <= 0:
+		return NullPrice, ""
+	case h < l:
+		return HighBelowLow, fmt.Sprintf("high %.2f low %.2f", h, l)
+	case c > h || c < l || o > h || o < l:
+		return OutsideRange, fmt.Sprintf("open %.2f close %.2f range %.2f-%.2f", o, c, l, h)
+	}
+	return 0, ""
+}
+
+func missingSessions(cal TradingCalendar, from time.Time, to time.Time) int {
+	n := 0
+	for d := from.AddDate(0, 0, 1); d.Before(to); d = d.AddDate(0, 0, 1) {
+		if cal.IsTradingDay(d) {
+			n++
+		}
+	}
+	return n
+}
+
+// unknownHolidays notes the years of a gap whose holidays the calendar
+// does not know, when it tells
+func unknownHolidays(cal TradingCalendar, from time.Time, to time.Time) string {
+	c, ok := cal.(coverage)
+	if !ok {
+		return ""
+	}
+	var years []string
+	for y := from.Year(); y <= to.Year(); y++ {
+		if !c.Covers(time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			years = append(years, strconv.Itoa(y))
+		}
+	}
+	if len(years) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", holidays of %s unknown", strings.Join(years, ", "))
+}
+
+// jumpDetail describes a jump, naming the split or bonus ratio it resembles
+func jumpDetail(prev float64, close float64) string {
+	detail := fmt.Sprintf("%.2f to %.2f", prev, close)
+	switch kind, n := resembledAction(prev / close); kind {
+	case Split:
+		return detail + fmt.Sprintf(", looks like an unadjusted %.0f:1 split", n)
+	case Bonus:
+		return detail + fmt.Sprintf(", looks like an unadjusted 1:%.0f bonus", n)
+	}
+	return detail
+}
+
+// Repair returns a sorted copy of the quotes with one session per date, or
+// per timestamp for intraday bars, and the unusable sessions handled by the
+// policy, along with the report of the quotes as they were. Missing
+// sessions and jumps are reported, not repaired.
+func (v *Validator) Repair(q *QuoteData, policy RepairPolicy) (*QuoteData, *ValidationReport) {
+	report := v.Validate(q)
+	r := sortedUnique(q, sessionKey(q))
+	bad := make([]bool, len(r.Dates))
+	for i := range r.Dates {
+		problem, _ := badBar(r, i)
+		bad[i] = problem != 0
+	}
+	switch policy {
+	case RepairForwardFill:
+		for i := range r.Dates {
+			if bad[i] && i > 0 && !bad[i-1] {
+				fillBar(r, i, r.Closes[i-1], r.AdjCloses[i-1])
+				bad[i] = false
+			}
+		}
+	case RepairInterpolate:
+		for i := 0; i < len(r.Dates); i++ {
+			if !bad[i] || i == 0 || bad[i-1] {
+				continue
+			}
+			next := i
+			for next < len(r.Dates) && bad[next] {
+				next++
+			}
+			for j := i; j < next; j++ {
+				if next == len(r.Dates) {
+					fillBar(r, j, r.Closes[i-1], r.AdjCloses[i-1])
+				} else {
+					w := float64(j-i+1) / float64(next-i+1)
+					fillBar(r, j, r.Closes[i-1]+w*(r.Closes[next]-r.Closes[i-1]),
+						r.AdjCloses[i-1]+w*(r.AdjCloses[next]-r.AdjCloses[i-1]))
+				}
+				bad[j] = false
+			}
+		}
+	}
+	// whatever is still bad, such as leading sessions, is dropped
+	keep := make([]int, 0, len(r.Dates))
+	for i := range r.Dates {
+		if !bad[i] {
+			keep = append(keep, i)
+		}
+	}
+	return r.pick(keep), report
+}
+
+func fillBar(q *QuoteData, i int, close float64, adjClose float64) {
+	q.Opens[i] = close
+	q.Highs[i] = close
+	q.Lows[i] = close
+	q.Closes[i] = close
+	q.AdjCloses[i] = adjClose
+	q.Volumes[i] = 0
+	fillExtras(q, i)
+}
+
+// fillExtras clears the activity of a session without trades; open
+// interest is carried over from the previous session
+func fillExtras(q *QuoteData, i int) {
+	for _, e := range q.Extras() {
+		values := q.Series(e)
+		values[i] = 0
+		if e == OpenInterest && i > 0 {
+			values[i] = values[i-1]
+		}
+	}
+}
+
+// sessionTime tells intraday bars apart by their full timestamp
+func sessionTime(t time.Time) time.Time {
+	return t
+}
+
+// sessionKey returns what tells the sessions of the quotes apart: the
+// calendar day of daily quotes, the timestamp of intraday bars
+func sessionKey(qs ...*QuoteData) func(time.Time) time.Time {
+	for _, q := range qs {
+		if q.IsIntraday() {
+			return sessionTime
+		}
+	}
+	return truncateDay
+}
+
+// sortedUnique returns a copy of the quotes in date order, keeping the last
+// of several sessions with the same key
+func sortedUnique(q *QuoteData, key func(time.Time) time.Time) *QuoteData {
+	order := make([]int, len(q.Dates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return key(q.Dates[order[a]]).Before(key(q.Dates[order[b]]))
+	})
+	keep := make([]int, 0, len(order))
+	for k, i := range order {
+		if k+1 < len(order) && key(q.Dates[order[k+1]]).Equal(key(q.Dates[i])) {
+			continue
+		}
+		keep = append(keep, i)
+	}
+	return q.pick(keep)
+}
+
+// pick returns a copy holding the sessions at the given indexes
+func (q *QuoteData) pick(index []int) *QuoteData {
+	hasAdj := len(q.AdjCloses) == len(q.Closes)
+	r := &QuoteData{
+		Symbol:    q.Symbol,
+		Dates:     make([]time.Time, len(index)),
+		Opens:     make([]float64, len(index)),
+		Highs:     make([]float64, len(index)),
+		Lows:      make([]float64, len(index)),
+		Closes:    make([]float64, len(index)),
+		AdjCloses: make([]float64, len(index)),
+		Volumes:   make([]float64, len(index)),
+	}
+	for k, i := range index {
+		r.Dates[k] = q.Dates[i]
+		r.Opens[k] = q.Opens[i]
+		r.Highs[k] = q.Highs[i]
+		r.Lows[k] = q.Lows[i]
+		r.Closes[k] = q.Closes[i]
+		r.AdjCloses[k] = q.Closes[i]
+		if hasAdj {
+			r.AdjCloses[k] = q.AdjCloses[i]
+		}
+		r.Volumes[k] = q.Volumes[i]
+	}
+	for _, e := range q.Extras() {
+		values, picked := q.Series(e), make([]float64, len(index))
+		for k, i := range index {
+			picked[k] = values[i]
+		}
+		*r.series(e) = picked
+	}
+	return r
+}
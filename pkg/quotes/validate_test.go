@@ -0,0 +1,183 @@
+package quotes
+
+import (
+	"pkg/calendar"
+	"testing"
+	"time"
+)
+
+// holidays is a calendar closed on its days, knowing the holidays of 2017 only
+type holidays []time.Time
+
+func (h holidays) IsTradingDay(date time.Time) bool {
+	for _, d := range h {
+		if d.Equal(date) {
+			return false
+		}
+	}
+	return weekdays{}.IsTradingDay(date)
+}
+
+func (h holidays) Covers(date time.Time) bool {
+	return date.Year() == 2017
+}
+
+func TestValidateHolidayGaps(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	sessions := func(dates ...time.Time) *QuoteData {
+		var qs []Quote
+		for _, d := range dates {
+			qs = append(qs, Quote{Date: d, Open: 100, High: 101, Low: 99, Close: 100, Volume: 1000})
+		}
+		return convert(qs, "TEST")
+	}
+	v := &Validator{Calendar: holidays{day(2017, 10, 20)}}
+
+	// Thursday to Monday over Friday's Diwali holiday
+	diwali := sessions(day(2017, 10, 19), day(2017, 10, 23))
+	if report := v.Validate(diwali); len(report.Issues) != 0 {
+		t.Errorf("issues over a holiday %v", report.Issues)
+	}
+	if report := (&Validator{}).Validate(diwali); len(report.Issues) != 1 || report.Issues[0].Kind != MissingSessions {
+		t.Errorf("weekdays: issues %v", report.Issues)
+	}
+
+	// Friday to Tuesday into a year whose holidays are unknown
+	newYear := sessions(day(2017, 12, 29), day(2018, 1, 2))
+	report := v.Validate(newYear)
+	if len(report.Issues) != 1 || report.Issues[0].Detail != "1 since 2017-12-29, holidays of 2018 unknown" {
+		t.Errorf("issues %v", report.Issues)
+	}
+}
+
+func TestNSEHolidayGaps(t *testing.T) {
+	nse, err := calendar.LoadHolidays("NSE", "../../holidays/NSE.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Generator{Symbol: "TEST", Start: time.Date(2017, 10, 2, 0, 0, 0, 0, time.UTC), Seed: 1,
+		Price: 100, Volume: 1e5, Calendar: nse}
+	qd := g.Generate(GBM{Volatility: 0.2}, 40)
+	for _, d := range qd.Dates {
+		if !nse.IsTradingDay(d) {
+			t.Errorf("session on %v", d)
+		}
+	}
+	if report := (&Validator{Calendar: nse}).Validate(qd); len(report.Issues) > 0 {
+		t.Errorf("issues across the holidays: %v", report.Issues)
+	}
+	// without the holidays Diwali Balipratipada looks like a missing session
+	report := (&Validator{}).Validate(qd)
+	if len(report.Issues) != 1 || !report.Issues[0].Date.Equal(time.Date(2017, 10, 23, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("weekday issues %v", report.Issues)
+	}
+}
+
+func TestValidateIntraday(t *testing.T) {
+	bar := func(d int, h int, m int) time.Time { return time.Date(2018, 1, d, h, m, 0, 0, IST) }
+	var qs []Quote
+	// five 5 minute bars on Monday, the 09:20 one twice, then Wednesday's
+	for _, d := range []time.Time{bar(1, 9, 15), bar(1, 9, 20), bar(1, 9, 20), bar(1, 9, 25), bar(1, 9, 30),
+		bar(1, 9, 35), bar(3, 9, 15), bar(3, 9, 20)} {
+		qs = append(qs, Quote{Date: d, Open: 100, High: 101, Low: 99, Close: 100, Volume: 1000})
+	}
+	q := convert(qs, "TEST")
+	repaired, report := (&Validator{}).Repair(q, RepairDrop)
+	if report.Count(DuplicateDate) != 1 || report.Count(MissingSessions) != 1 || len(report.Issues) != 2 {
+		t.Errorf("issues %v", report.Issues)
+	}
+	if report.Issues[1].Detail != "1 since 2018-01-01" {
+		t.Errorf("missing sessions %q", report.Issues[1].Detail)
+	}
+	if len(repaired.Dates) != 7 || !repaired.Dates[1].Equal(bar(1, 9, 20)) || !repaired.Dates[6].Equal(bar(3, 9, 20)) {
+		t.Errorf("repaired to %v", repaired.Dates)
+	}
+
+	// a bar out of order is reported against the time of the one before
+	q.Dates[4], q.Dates[5] = q.Dates[5], q.Dates[4]
+	report = (&Validator{}).Validate(q)
+	if report.Count(OutOfOrder) != 1 || report.Issues[1].Detail != "after 2018-01-01 09:35" {
+		t.Errorf("issues %v", report.Issues)
+	}
+}
+
+func TestRepair(t *testing.T) {
+	// a close of 0 is a null bar
+	for _, c := range []struct {
+		name   string
+		policy RepairPolicy
+		closes []float64
+		want   []float64
+		filled []bool
+	}{
+		{"drop", RepairDrop, []float64{10, 0, 12}, []float64{10, 12}, []bool{false, false}},
+		{"forward fill", RepairForwardFill, []float64{10, 0, 0, 13}, []float64{10, 10, 10, 13}, []bool{false, true, true, false}},
+		{"interpolate", RepairInterpolate, []float64{10, 0, 0, 13}, []float64{10, 11, 12, 13}, []bool{false, true, true, false}},
+		{"interpolate trailing", RepairInterpolate, []float64{10, 11, 0, 0}, []float64{10, 11, 11, 11}, []bool{false, false, true, true}},
+		{"forward fill leading", RepairForwardFill, []float64{0, 0, 10, 11}, []float64{10, 11}, []bool{false, false}},
+		{"interpolate leading", RepairInterpolate, []float64{0, 10, 0, 12}, []float64{10, 11, 12}, []bool{false, true, false}},
+	} {
+		repaired, report := (&Validator{}).Repair(daily(c.closes...), c.policy)
+		nulls := 0
+		for _, close := range c.closes {
+			if close == 0 {
+				nulls++
+			}
+		}
+		if report.Count(NullPrice) != nulls {
+			t.Errorf("%s: issues %v", c.name, report.Issues)
+		}
+		if len(repaired.Closes) != len(c.want) {
+			t.Errorf("%s: closes %v, want %v", c.name, repaired.Closes, c.want)
+			continue
+		}
+		for i, want := range c.want {
+			if !near(repaired.Closes[i], want) || !near(repaired.AdjCloses[i], want) {
+				t.Errorf("%s: close %d is %.2f, want %.2f", c.name, i, repaired.Closes[i], want)
+			}
+			if filled := repaired.Volumes[i] == 0; filled != c.filled[i] {
+				t.Errorf("%s: volume %d is %.0f", c.name, i, repaired.Volumes[i])
+			}
+		}
+	}
+}
+
+func TestBadBar(t *testing.T) {
+	for _, c := range []struct {
+		o, h, l, c float64
+		kind       IssueKind
+		detail     string
+	}{
+		{100, 101, 99, 100, 0, ""},
+		{100, 100, 100, 100, 0, ""},
+		{0, 101, 99, 100, NullPrice, ""},
+		{100, 101, 99, -1, NullPrice, ""},
+		{100, 99, 101, 100, HighBelowLow, "high 99.00 low 101.00"},
+		{100, 101, 99, 102, OutsideRange, "open 100.00 close 102.00 range 99.00-101.00"},
+		{98, 101, 99, 100, OutsideRange, "open 98.00 close 100.00 range 99.00-101.00"},
+	} {
+		q := convert([]Quote{{Date: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+			Open: c.o, High: c.h, Low: c.l, Close: c.c, Volume: 1000}}, "TEST")
+		if kind, detail := badBar(q, 0); kind != c.kind || detail != c.detail {
+			t.Errorf("%v %v %v %v: %v %q, want %v %q", c.o, c.h, c.l, c.c, kind, detail, c.kind, c.detail)
+		}
+	}
+}
+
+func TestJumpDetail(t *testing.T) {
+	for _, c := range []struct {
+		prev, close float64
+		want        string
+	}{
+		{200, 100, "200.00 to 100.00, looks like an unadjusted 2:1 split"},
+		{1000, 101, "1000.00 to 101.00, looks like an unadjusted 10:1 split"},
+		{150, 100, "150.00 to 100.00, looks like an unadjusted 1:2 bonus"},
+		{400, 300, "400.00 to 300.00, looks like an unadjusted 1:3 bonus"},
+		{100, 140, "100.00 to 140.00"},
+		{100, 60, "100.00 to 60.00"},
+	} {
+		if got := jumpDetail(c.prev, c.close); got != c.want {
+			t.Errorf("%v to %v: %q, want %q", c.prev, c.close, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package quotes
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Write writes the quotes in the format of the schema: its preamble and
+// header, column order, delimiter and date layout. Sessions without prices
+// are written with the schema's null marker.
+func (s *Schema) Write(w io.Writer, qd *QuoteData) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = s.Delimiter
+	writer.UseCRLF = s.CRLF
+	for _, record := range s.Preamble {
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	if s.Header {
+		header := s.HeaderLine
+		if header == nil {
+			header = s.defaultHeader()
+		}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+	null := "null"
+	if len(s.Nulls) > 0 {
+		null = s.Nulls[0]
+	}
+	c := s.Columns
+	record := make([]string, s.width())
+	for i := range qd.Dates {
+		q := qd.Quote(i)
+		empty := q.Open == 0 && q.High == 0 && q.Low == 0 && q.Close == 0
+		date := q.Date
+		if s.Location != nil {
+			date = date.In(s.Location)
+		}
+		record[c.Date] = date.Format(s.DateLayout)
+		columns := []int{c.Open, c.High, c.Low, c.Close, c.AdjClose, c.Volume}
+		values := []float64{q.Open, q.High, q.Low, q.Close, q.AdjClose, q.Volume}
+		for k, v := range values {
+			if columns[k] < 0 {
+				continue
+			}
+			switch {
+			case empty:
+				record[columns[k]] = null
+			case columns[k] == c.Volume:
+				record[columns[k]] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				record[columns[k]] = strconv.FormatFloat(v, 'f', s.Precision, 64)
+			}
+		}
+		for _, e := range c.extras() {
+			if empty || !qd.Has(e) {
+				record[c.column(e)] = null
+			} else {
+				record[c.column(e)] = strconv.FormatFloat(*q.value(e), 'f', -1, 64)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// seriesHeader names the columns of the optional series in written files
+var seriesHeader = map[Series]string{
+	Delivery:     "Deliverable Qty",
+	DeliveryPct:  "Delivery %",
+	Turnover:     "Turnover",
+	OpenInterest: "Open Interest",
+}
+
+func (s *Schema) defaultHeader() []string {
+	header := make([]string, s.width())
+	c := s.Columns
+	names := []string{"Date", "Open", "High", "Low", "Close", "Adj Close", "Volume",
+		seriesHeader[Delivery], seriesHeader[DeliveryPct], seriesHeader[Turnover], seriesHeader[OpenInterest]}
+	for k, i := range []int{c.Date, c.Open, c.High, c.Low, c.Close, c.AdjClose, c.Volume,
+		c.Delivery, c.DeliveryPct, c.Turnover, c.OpenInterest} {
+		if i >= 0 {
+			header[i] = names[k]
+		}
+	}
+	return header
+}
+
+// WriteFile replaces the file with the quotes written in the schema's format
+func (s *Schema) WriteFile(file string, qd *QuoteData) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	err = tmp.Chmod(0644)
+	if err == nil {
+		err = s.Write(w, qd)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
@@ -0,0 +1,311 @@
+// Package store keeps quote histories in a local SQLite file
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"pkg/quotes"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS quotes (
+	symbol    TEXT    NOT NULL,
+	date      INTEGER NOT NULL,
+	open      REAL    NOT NULL,
+	high      REAL    NOT NULL,
+	low       REAL    NOT NULL,
+	close     REAL    NOT NULL,
+	adj_close REAL    NOT NULL,
+	volume    REAL    NOT NULL,
+	PRIMARY KEY (symbol, date)
+);
+CREATE TABLE IF NOT EXISTS activity (
+	symbol        TEXT    NOT NULL,
+	date          INTEGER NOT NULL,
+	delivery      REAL,
+	delivery_pct  REAL,
+	turnover      REAL,
+	open_interest REAL,
+	trades        REAL,
+	PRIMARY KEY (symbol, date)
+)`
+
+// version is the user_version of a store with the schema above. Stores of
+// version 1 and before have an activity table of turnover, delivery and
+// trades only; migrate adds the columns they lack.
+const version = 2
+
+// Store is a quote database with one row per symbol and session
+type Store struct {
+	db *sql.DB
+}
+
+// Coverage describes the quotes held for a symbol
+type Coverage struct {
+	Symbol string
+	First  time.Time
+	Last   time.Time
+	Rows   int
+}
+
+// Activity is the trading in a session beyond its volume. NaN values are
+// stored as NULL.
+type Activity struct {
+	Date     time.Time
+	Turnover float64
+	Delivery float64 // deliverable quantity
+	Trades   float64
+}
+
+// Open opens the store, creating the file and its tables when missing and
+// bringing the tables of an older store up to date
+func Open(file string) (*Store, error) {
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: migrating the store: %v", file, err)
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	var v int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&v); err != nil {
+		return err
+	}
+	if v >= version {
+		return nil
+	}
+	rows, err := db.Query("PRAGMA table_info(activity)")
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var cid, notnull, pk int
+		var name, kind string
+		var value sql.NullString
+		if err := rows.Scan(&cid, &name, &kind, &notnull, &value, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		columns[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, c := range []string{"delivery", "delivery_pct", "turnover", "open_interest", "trades"} {
+		if !columns[c] {
+			if _, err := db.Exec("ALTER TABLE activity ADD COLUMN " + c + " REAL"); err != nil {
+				return err
+			}
+		}
+	}
+	_, err = db.Exec(fmt.Sprintf("PRAGMA user_version = %d", version))
+	return err
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Upsert inserts the quotes, replacing the sessions the store already has
+// for the symbol and date. Optional series go to the activity table, where
+// the series the quotes lack are NULL, also for the sessions replaced. The
+// trade count of UpsertActivity is kept. It returns the number of rows written.
+func (s *Store) Upsert(qd *quotes.QuoteData) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO quotes
+		(symbol, date, open, high, low, close, adj_close, volume)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	defer stmt.Close()
+	activity, err := tx.Prepare(`INSERT INTO activity
+		(symbol, date, delivery, delivery_pct, turnover, open_interest)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (symbol, date) DO UPDATE SET delivery = excluded.delivery,
+			delivery_pct = excluded.delivery_pct, turnover = excluded.turnover,
+			open_interest = excluded.open_interest`)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	defer activity.Close()
+	reset, err := tx.Prepare(`UPDATE activity SET delivery = NULL, delivery_pct = NULL,
+		turnover = NULL, open_interest = NULL WHERE symbol = ? AND date = ?`)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	defer reset.Close()
+	extras := len(qd.Extras()) > 0
+	for i := range qd.Dates {
+		q := qd.Quote(i)
+		if _, err := stmt.Exec(qd.Symbol, q.Date.Unix(), q.Open, q.High, q.Low, q.Close, q.AdjClose, q.Volume); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if !extras {
+			if _, err := reset.Exec(qd.Symbol, q.Date.Unix()); err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+			continue
+		}
+		values := []interface{}{qd.Symbol, q.Date.Unix()}
+		for _, series := range quotes.AllSeries {
+			if qd.Has(series) {
+				values = append(values, qd.Series(series)[i])
+			} else {
+				values = append(values, nil)
+			}
+		}
+		if _, err := activity.Exec(values...); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM activity WHERE symbol = ? AND delivery IS NULL AND delivery_pct IS NULL
+		AND turnover IS NULL AND open_interest IS NULL AND trades IS NULL`, qd.Symbol); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return len(qd.Dates), tx.Commit()
+}
+
+// UpsertActivity sets the turnover, deliverable quantity and trade count
+// of the sessions of a symbol, leaving its other series as they are
+func (s *Store) UpsertActivity(symbol string, activity []Activity) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT INTO activity (symbol, date, turnover, delivery, trades)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (symbol, date) DO UPDATE SET turnover = excluded.turnover,
+			delivery = excluded.delivery, trades = excluded.trades`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, a := range activity {
+		if _, err := stmt.Exec(symbol, a.Date.Unix(), nullable(a.Turnover), nullable(a.Delivery), nullable(a.Trades)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func nullable(v float64) interface{} {
+	if math.IsNaN(v) {
+		return nil
+	}
+	return v
+}
+
+// ImportFile loads a quote file of any known format and upserts its quotes
+func (s *Store) ImportFile(file string, symbol string) (int, *quotes.LoadReport, error) {
+	qd, report, err := quotes.LoadFileWithSchema(file, symbol, nil)
+	if err != nil {
+		return 0, report, err
+	}
+	n, err := s.Upsert(qd)
+	return n, report, err
+}
+
+// Load returns the quotes of a symbol from one date to another, both
+// included. A zero from or to leaves that end open.
+func (s *Store) Load(symbol string, from time.Time, to time.Time) (*quotes.QuoteData, error) {
+	query := `SELECT q.date, open, high, low, close, adj_close, volume,
+			delivery, delivery_pct, turnover, open_interest
+		FROM quotes q LEFT JOIN activity a ON a.symbol = q.symbol AND a.date = q.date
+		WHERE q.symbol = ? AND q.date >= ? AND q.date <= ? ORDER BY q.date`
+	start, end := int64(0), int64(1<<62)
+	if !from.IsZero() {
+		start = from.Unix()
+	}
+	if !to.IsZero() {
+		end = to.Unix()
+	}
+	rows, err := s.db.Query(query, symbol, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	qd := &quotes.QuoteData{Symbol: symbol}
+	// an optional series is returned when any session has it
+	extras := make([][]sql.NullFloat64, len(quotes.AllSeries))
+	for rows.Next() {
+		var date int64
+		var q quotes.Quote
+		values := make([]sql.NullFloat64, len(quotes.AllSeries))
+		if err := rows.Scan(&date, &q.Open, &q.High, &q.Low, &q.Close, &q.AdjClose, &q.Volume,
+			&values[0], &values[1], &values[2], &values[3]); err != nil {
+			return nil, err
+		}
+		for k, v := range values {
+			extras[k] = append(extras[k], v)
+		}
+		qd.Dates = append(qd.Dates, time.Unix(date, 0).UTC())
+		qd.Opens = append(qd.Opens, q.Open)
+		qd.Highs = append(qd.Highs, q.High)
+		qd.Lows = append(qd.Lows, q.Low)
+		qd.Closes = append(qd.Closes, q.Close)
+		qd.AdjCloses = append(qd.AdjCloses, q.AdjClose)
+		qd.Volumes = append(qd.Volumes, q.Volume)
+	}
+	for k, values := range extras {
+		held := false
+		series := make([]float64, len(values))
+		for i, v := range values {
+			held = held || v.Valid
+			series[i] = v.Float64
+		}
+		if held {
+			qd.SetSeries(quotes.AllSeries[k], series)
+		}
+	}
+	return qd, rows.Err()
+}
+
+// Symbols returns the date coverage and row count of every symbol
+func (s *Store) Symbols() ([]Coverage, error) {
+	rows, err := s.db.Query(`SELECT symbol, MIN(date), MAX(date), COUNT(*)
+		FROM quotes GROUP BY symbol ORDER BY symbol`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var coverage []Coverage
+	for rows.Next() {
+		var c Coverage
+		var first, last int64
+		if err := rows.Scan(&c.Symbol, &first, &last, &c.Rows); err != nil {
+			return nil, err
+		}
+		c.First = time.Unix(first, 0).UTC()
+		c.Last = time.Unix(last, 0).UTC()
+		coverage = append(coverage, c)
+	}
+	return coverage, rows.Err()
+}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"pkg/quotes"
+	"testing"
+	"time"
+)
+
+func TestMigrate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "quotes.db")
+	db, err := sql.Open("sqlite3", file)
+	if err == nil {
+		err = db.Ping()
+	}
+	if err != nil {
+		t.Skipf("no sqlite: %v", err)
+	}
+	// the activity table of the first stores
+	_, err = db.Exec(`CREATE TABLE activity (symbol TEXT NOT NULL, date INTEGER NOT NULL,
+		turnover REAL, delivery REAL, trades REAL, PRIMARY KEY (symbol, date))`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	day := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := s.UpsertActivity("TEST", []Activity{{Date: day, Turnover: 1e7, Delivery: math.NaN(), Trades: 500}}); err != nil {
+		t.Fatal(err)
+	}
+	g := &quotes.Generator{Symbol: "TEST", Start: day, Seed: 1, Price: 100}
+	qd := g.Generate(quotes.GBM{Volatility: 0.2}, 3)
+	qd.SetSeries(quotes.DeliveryPct, []float64{40, 50, 60})
+	if _, err := s.Upsert(qd); err != nil {
+		t.Fatal(err)
+	}
+	var trades float64
+	if err := s.db.QueryRow("SELECT trades FROM activity WHERE symbol = 'TEST' AND date = ?", day.Unix()).Scan(&trades); err != nil || trades != 500 {
+		t.Errorf("trades %v, %v", trades, err)
+	}
+	loaded, err := s.Load("TEST", time.Time{}, time.Time{})
+	if err != nil || !loaded.Has(quotes.DeliveryPct) || loaded.DeliveryPcts[2] != 60 {
+		t.Fatalf("loaded %v, %v", loaded, err)
+	}
+
+	// quotes without the series clear them from the sessions replaced
+	if _, err := s.Upsert(g.Generate(quotes.GBM{Volatility: 0.2}, 3)); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = s.Load("TEST", time.Time{}, time.Time{})
+	if err != nil || loaded.Has(quotes.DeliveryPct) {
+		t.Errorf("stale delivery %v, %v", loaded.DeliveryPcts, err)
+	}
+	var rows int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM activity").Scan(&rows); err != nil || rows != 1 {
+		t.Errorf("%d activity rows, %v", rows, err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "quotes.db")
+	if db, err := sql.Open("sqlite3", file); err != nil || db.Ping() != nil {
+		t.Skip("no sqlite")
+	} else {
+		db.Close()
+	}
+	s, err := Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	day := func(d int) time.Time { return time.Date(2018, 1, d, 0, 0, 0, 0, time.UTC) }
+	generate := func(symbol string, start int, seed int64, n int) *quotes.QuoteData {
+		g := &quotes.Generator{Symbol: symbol, Start: day(start), Seed: seed, Price: float64(100 * seed)}
+		return g.Generate(quotes.GBM{Volatility: 0.2}, n)
+	}
+	// the 1st to the 12th, then the 8th to the 16th replacing the second week
+	first, second := generate("AAA", 1, 1, 10), generate("AAA", 8, 2, 7)
+	for _, qd := range []*quotes.QuoteData{first, second, generate("BBB", 1, 3, 3)} {
+		if n, err := s.Upsert(qd); err != nil || n != len(qd.Dates) {
+			t.Fatalf("upserted %d of %d, %v", n, len(qd.Dates), err)
+		}
+	}
+
+	want := append(append([]time.Time{}, first.Dates[:5]...), second.Dates...)
+	closes := append(append([]float64{}, first.Closes[:5]...), second.Closes...)
+	loaded, err := s.Load("AAA", time.Time{}, time.Time{})
+	if err != nil || len(loaded.Dates) != len(want) {
+		t.Fatalf("loaded %v, %v", loaded, err)
+	}
+	for i := range want {
+		if !loaded.Dates[i].Equal(want[i]) || loaded.Closes[i] != closes[i] {
+			t.Errorf("session %d: %v %.2f, want %v %.2f", i, loaded.Dates[i], loaded.Closes[i], want[i], closes[i])
+		}
+	}
+
+	for _, c := range []struct {
+		from, to time.Time
+		n        int
+	}{
+		{day(10), time.Time{}, 5},
+		{time.Time{}, day(3), 3},
+		{day(3), day(9), 5},
+		{day(6), day(7), 0},
+		{day(20), time.Time{}, 0},
+	} {
+		if loaded, err := s.Load("AAA", c.from, c.to); err != nil || len(loaded.Dates) != c.n {
+			t.Errorf("from %v to %v: %d sessions, want %d, %v", c.from, c.to, len(loaded.Dates), c.n, err)
+		}
+	}
+	if loaded, err := s.Load("CCC", time.Time{}, time.Time{}); err != nil || len(loaded.Dates) != 0 {
+		t.Errorf("unknown symbol %v, %v", loaded, err)
+	}
+
+	coverage, err := s.Symbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCoverage := []Coverage{{"AAA", day(1), day(16), 12}, {"BBB", day(1), day(3), 3}}
+	if len(coverage) != len(wantCoverage) {
+		t.Fatalf("coverage %v", coverage)
+	}
+	for i, c := range wantCoverage {
+		if got := coverage[i]; got.Symbol != c.Symbol || !got.First.Equal(c.First) || !got.Last.Equal(c.Last) || got.Rows != c.Rows {
+			t.Errorf("coverage %v, want %v", got, c)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Package symbols is the symbol master: what is known about each listed
+// security and the names it goes by
+package symbols
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"pkg/calendar"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Symbol describes a listed security
+type Symbol struct {
+	Symbol   string // exchange ticker, such as BAJFINANCE
+	Exchange string
+	Aliases  []string // other names, such as a Yahoo ticker or a BSE scrip code
+	ISIN     string
+	Name     string
+	Sector   string
+	Industry string
+	TickSize float64
+	LotSize  int
+	Listed   time.Time // zero when unknown
+	Delisted time.Time // zero while listed
+}
+
+// Suffixes are the Yahoo ticker suffixes of the exchanges
+var Suffixes = map[string]string{
+	"NSE": ".NS",
+	"BSE": ".BO",
+}
+
+// Yahoo returns the Yahoo ticker of the symbol, such as BAJFINANCE.NS
+func (s *Symbol) Yahoo() string {
+	return s.Symbol + Suffixes[s.Exchange]
+}
+
+// Names returns the ticker, the Yahoo ticker and the aliases of the symbol
+func (s *Symbol) Names() []string {
+	names := []string{s.Symbol}
+	if y := s.Yahoo(); y != s.Symbol {
+		names = append(names, y)
+	}
+	for _, a := range s.Aliases {
+		if a != s.Symbol && a != s.Yahoo() {
+			names = append(names, a)
+		}
+	}
+	return names
+}
+
+// ListedOn returns true when the symbol traded on the date
+func (s *Symbol) ListedOn(date time.Time) bool {
+	if !s.Listed.IsZero() && date.Before(s.Listed) {
+		return false
+	}
+	return s.Delisted.IsZero() || date.Before(s.Delisted)
+}
+
+// Master resolves symbol names to the symbols they stand for
+type Master struct {
+	Exchange string // exchange of names without a known suffix
+	symbols  map[string]*Symbol
+	names    map[string]*Symbol
+}
+
+// New returns an empty master for an exchange. It still resolves Yahoo
+// tickers of that exchange, such as BAJFINANCE.NS, to the plain ticker.
+func New(exchange string) *Master {
+	return &Master{
+		Exchange: exchange,
+		symbols:  make(map[string]*Symbol),
+		names:    make(map[string]*Symbol),
+	}
+}
+
+// Add adds a symbol, replacing one with the same ticker
+func (m *Master) Add(s *Symbol) {
+	if s.Exchange == "" {
+		s.Exchange = m.Exchange
+	}
+	m.symbols[s.Symbol] = s
+	for _, name := range s.Names() {
+		m.names[strings.ToUpper(name)] = s
+	}
+	if s.ISIN != "" {
+		m.names[strings.ToUpper(s.ISIN)] = s
+	}
+}
+
+// Lookup returns the symbol a ticker, Yahoo ticker, alias or ISIN stands for
+func (m *Master) Lookup(name string) (*Symbol, bool) {
+	s, ok := m.names[strings.ToUpper(strings.TrimSpace(name))]
+	return s, ok
+}
+
+// Resolve returns the symbol a name stands for. A name the master does not
+// know gives a symbol of its own, with the exchange taken from its suffix.
+func (m *Master) Resolve(name string) *Symbol {
+	if s, ok := m.Lookup(name); ok {
+		return s
+	}
+	name = strings.ToUpper(strings.TrimSpace(name))
+	for exchange, suffix := range Suffixes {
+		if strings.HasSuffix(name, suffix) {
+			if s, ok := m.Lookup(strings.TrimSuffix(name, suffix)); ok && s.Exchange == exchange {
+				return s
+			}
+			return &Symbol{Symbol: strings.TrimSuffix(name, suffix), Exchange: exchange}
+		}
+	}
+	return &Symbol{Symbol: name, Exchange: m.Exchange}
+}
+
+// Ticker returns the ticker a name stands for, such as BAJFINANCE for BAJFINANCE.NS
+func (m *Master) Ticker(name string) string {
+	return m.Resolve(name).Symbol
+}
+
+// Symbols returns the symbols of the master in ticker order
+func (m *Master) Symbols() []*Symbol {
+	list := make([]*Symbol, 0, len(m.symbols))
+	for _, s := range m.symbols {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Symbol < list[j].Symbol })
+	return list
+}
+
+// Sector returns the symbols of a sector in ticker order
+func (m *Master) Sector(sector string) []*Symbol {
+	var list []*Symbol
+	for _, s := range m.Symbols() {
+		if strings.EqualFold(s.Sector, sector) {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// DefaultFile is the symbol master of the NSE kept with the project
+const DefaultFile = "symbols/NSE.csv"
+
+// LoadOrEmpty reads a symbol master, or returns an empty one for the
+// exchange when the file cannot be read
+func LoadOrEmpty(file string, exchange string) *Master {
+	m, err := Load(file, exchange)
+	if err != nil {
+		log.Printf("No symbol master, symbols are taken as %s tickers: %v", exchange, err)
+		return New(exchange)
+	}
+	return m
+}
+
+// Load reads the symbol master of an exchange, which symbols without an
+// exchange of their own are listed on. Each line describes a symbol;
+// aliases are separated by |, dates are dd-mm-yyyy or yyyy-mm-dd and empty
+// fields are unknown. Lines starting with # are ignored:
+//
+//	# Symbol,Exchange,Aliases,ISIN,Name,Sector,Industry,TickSize,LotSize,Listed,Delisted
+//	BAJFINANCE,NSE,BAJFINANCE.NS,INE296A01024,Bajaj Finance Ltd,Financial Services,Finance,0.05,1,,
+func Load(file string, exchange string) (*Master, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m := New(exchange)
+	reader := csv.NewReader(bufio.NewReader(f))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	for {
+		line, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		lineno, _ := reader.FieldPos(0)
+		s, err := parseSymbol(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: %v", file, lineno, err)
+		}
+		m.Add(s)
+	}
+	return m, nil
+}
+
+func parseSymbol(line []string) (*Symbol, error) {
+	field := func(i int) string {
+		if i < len(line) {
+			return strings.TrimSpace(line[i])
+		}
+		return ""
+	}
+	s := &Symbol{
+		Symbol:   strings.ToUpper(field(0)),
+		Exchange: strings.ToUpper(field(1)),
+		ISIN:     field(3),
+		Name:     field(4),
+		Sector:   field(5),
+		Industry: field(6),
+		LotSize:  1,
+	}
+	if s.Symbol == "" {
+		return nil, fmt.Errorf("no symbol")
+	}
+	for _, a := range strings.Split(field(2), "|") {
+		if a = strings.TrimSpace(a); a != "" {
+			s.Aliases = append(s.Aliases, a)
+		}
+	}
+	var err error
+	if v := field(7); v != "" {
+		if s.TickSize, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("tick size: %v", err)
+		}
+	}
+	if v := field(8); v != "" {
+		if s.LotSize, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("lot size: %v", err)
+		}
+	}
+	if v := field(9); v != "" {
+		if s.Listed, err = calendar.ParseDate(v); err != nil {
+			return nil, fmt.Errorf("listing date: %v", err)
+		}
+	}
+	if v := field(10); v != "" {
+		if s.Delisted, err = calendar.ParseDate(v); err != nil {
+			return nil, fmt.Errorf("delisting date: %v", err)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,135 @@
+package symbols
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func write(t *testing.T, lines ...string) string {
+	file := filepath.Join(t.TempDir(), "NSE.csv")
+	if err := os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	m, err := Load(write(t,
+		"# Symbol,Exchange,Aliases,ISIN,Name,Sector,Industry,TickSize,LotSize,Listed,Delisted",
+		"BAJFINANCE,NSE,BAJFINANCE.NS|500034,INE296A01024,Bajaj Finance Ltd,Financial Services,Finance,0.05,1,,",
+		"bajaj-auto,,,INE917I01010,Bajaj Auto Ltd,Automobile,,0.05,,2008-05-26,",
+		"HDIL,NSE,,,Housing Development and Infrastructure Ltd,Realty,,0.05,1,01-07-2007,12-10-2020",
+		"RELIANCE,BSE,500325,,,,,,,,",
+	), "NSE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(m.Symbols()); n != 4 || m.Symbols()[0].Symbol != "BAJAJ-AUTO" {
+		t.Fatalf("%d symbols %v", n, m.Symbols())
+	}
+
+	for _, c := range []struct {
+		name   string
+		symbol string
+		known  bool
+	}{
+		{"BAJFINANCE", "BAJFINANCE", true},
+		{"BAJFINANCE.NS", "BAJFINANCE", true},
+		{" bajfinance.ns ", "BAJFINANCE", true},
+		{"500034", "BAJFINANCE", true},
+		{"INE296A01024", "BAJFINANCE", true},
+		{"BAJAJ-AUTO.NS", "BAJAJ-AUTO", true},
+		{"RELIANCE.BO", "RELIANCE", true},
+		{"500325", "RELIANCE", true},
+		{"RELIANCE.NS", "RELIANCE", false}, // the master knows its BSE listing only
+		{"TCS", "TCS", false},
+		{"TCS.NS", "TCS", false},
+		{"TCS.BO", "TCS", false},
+	} {
+		_, known := m.Lookup(c.name)
+		if known != c.known || m.Ticker(c.name) != c.symbol {
+			t.Errorf("%q: %s known %v, want %s known %v", c.name, m.Ticker(c.name), known, c.symbol, c.known)
+		}
+	}
+	for name, exchange := range map[string]string{"RELIANCE.NS": "NSE", "TCS": "NSE", "TCS.BO": "BSE", "RELIANCE": "BSE"} {
+		if s := m.Resolve(name); s.Exchange != exchange {
+			t.Errorf("%s on %s, want %s", name, s.Exchange, exchange)
+		}
+	}
+
+	bajaj := m.Resolve("BAJAJ-AUTO")
+	if bajaj.Exchange != "NSE" || bajaj.LotSize != 1 || bajaj.TickSize != 0.05 || bajaj.Sector != "Automobile" {
+		t.Errorf("defaults of %+v", bajaj)
+	}
+	if names := strings.Join(m.Resolve("BAJFINANCE").Names(), " "); names != "BAJFINANCE BAJFINANCE.NS 500034" {
+		t.Errorf("names %s", names)
+	}
+	if names := strings.Join(m.Resolve("RELIANCE").Names(), " "); names != "RELIANCE RELIANCE.BO 500325" {
+		t.Errorf("names %s", names)
+	}
+	if names := strings.Join(m.Resolve("TCS").Names(), " "); names != "TCS TCS.NS" {
+		t.Errorf("names of an unknown symbol %s", names)
+	}
+
+	hdil := m.Resolve("HDIL")
+	for _, c := range []struct {
+		symbol *Symbol
+		date   time.Time
+		want   bool
+	}{
+		{hdil, date(2007, 6, 29), false},
+		{hdil, date(2007, 7, 1), true},
+		{hdil, date(2020, 10, 9), true},
+		{hdil, date(2020, 10, 12), false},
+		{hdil, date(2021, 1, 1), false},
+		{bajaj, date(2008, 5, 23), false},
+		{bajaj, date(2024, 1, 1), true},
+		{m.Resolve("TCS"), date(1990, 1, 1), true},
+	} {
+		if got := c.symbol.ListedOn(c.date); got != c.want {
+			t.Errorf("%s listed on %v: %v", c.symbol.Symbol, c.date.Format("2006-01-02"), got)
+		}
+	}
+}
+
+func TestLoadMalformed(t *testing.T) {
+	for _, c := range []struct {
+		line string
+		err  string
+	}{
+		{",NSE,,,,,,,,,", "line 3: no symbol"},
+		{"ABC,NSE,,,,,,x,1,,", "line 3: tick size"},
+		{"ABC,NSE,,,,,,0.05,one,,", "line 3: lot size"},
+		{"ABC,NSE,,,,,,0.05,1,2008-13-01,", "line 3: listing date"},
+		{"ABC,NSE,,,,,,0.05,1,,yesterday", "line 3: delisting date"},
+		{`ABC,"NSE,,`, "line 3"},
+	} {
+		_, err := Load(write(t, "# a comment", "XYZ,NSE", c.line), "NSE")
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: %v, want %s", c.line, err, c.err)
+		}
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "none.csv"), "NSE"); err == nil {
+		t.Error("no error without the file")
+	}
+	if m := LoadOrEmpty(filepath.Join(t.TempDir(), "none.csv"), "NSE"); m.Exchange != "NSE" || len(m.Symbols()) != 0 {
+		t.Errorf("empty master %+v", m)
+	}
+}
+
+func TestDefaultFile(t *testing.T) {
+	m, err := Load(filepath.Join("..", "..", DefaultFile), "NSE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Ticker("BAJFINANCE.NS") != "BAJFINANCE" {
+		t.Errorf("symbols %v", m.Symbols())
+	}
+}
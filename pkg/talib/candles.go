@@ -0,0 +1,633 @@
+package talib
+
+import "math"
+
+// CandleRange is the part of the candles a setting averages
+type CandleRange int
+
+const (
+	RealBody CandleRange = iota + 1 // |close - open|
+	HighLow                         // high - low
+	Shadows                         // upper and lower shadows
+)
+
+// CandleSetting decides when a body or shadow is long, short or a doji:
+// Factor times the average range of the Period candles before, or of the
+// candle itself when Period is 0
+type CandleSetting struct {
+	Range  CandleRange
+	Period int
+	Factor float64
+}
+
+// CandleSettings are the thresholds of the candlestick patterns
+type CandleSettings struct {
+	BodyLong        CandleSetting // real body is long when longer than this
+	BodyVeryLong    CandleSetting // real body is very long when longer than this
+	BodyShort       CandleSetting // real body is short when shorter than this
+	BodyDoji        CandleSetting // real body is a doji when not longer than this
+	ShadowLong      CandleSetting // shadow is long when longer than this
+	ShadowVeryLong  CandleSetting // shadow is very long when longer than this
+	ShadowShort     CandleSetting // shadow is short when shorter than this
+	ShadowVeryShort CandleSetting // shadow is very short when shorter than this
+	Near            CandleSetting // prices are near each other when closer than this
+	Far             CandleSetting // prices are far from each other when further than this
+	Equal           CandleSetting // prices are equal when closer than this
+}
+
+// DefaultCandleSettings are the settings of TA-Lib, which the Cdl functions
+// use
+var DefaultCandleSettings = CandleSettings{
+	BodyLong:        CandleSetting{RealBody, 10, 1.0},
+	BodyVeryLong:    CandleSetting{RealBody, 10, 3.0},
+	BodyShort:       CandleSetting{RealBody, 10, 1.0},
+	BodyDoji:        CandleSetting{HighLow, 10, 0.1},
+	ShadowLong:      CandleSetting{RealBody, 0, 1.0},
+	ShadowVeryLong:  CandleSetting{RealBody, 0, 2.0},
+	ShadowShort:     CandleSetting{Shadows, 10, 1.0},
+	ShadowVeryShort: CandleSetting{HighLow, 10, 0.1},
+	Near:            CandleSetting{HighLow, 5, 0.2},
+	Far:             CandleSetting{HighLow, 5, 0.6},
+	Equal:           CandleSetting{HighLow, 5, 0.05},
+}
+
+// candles reads the shapes of the candles of a series
+type candles struct {
+	inOpen  []float64
+	inHigh  []float64
+	inLow   []float64
+	inClose []float64
+}
+
+func (c *candles) body(i int) float64 {
+	return math.Abs(c.inClose[i] - c.inOpen[i])
+}
+
+func (c *candles) top(i int) float64 {
+	return math.Max(c.inClose[i], c.inOpen[i])
+}
+
+func (c *candles) bottom(i int) float64 {
+	return math.Min(c.inClose[i], c.inOpen[i])
+}
+
+func (c *candles) upperShadow(i int) float64 {
+	return c.inHigh[i] - c.top(i)
+}
+
+func (c *candles) lowerShadow(i int) float64 {
+	return c.bottom(i) - c.inLow[i]
+}
+
+// color is 1 for a white candle and -1 for a black one
+func (c *candles) color(i int) float64 {
+	if c.inClose[i] >= c.inOpen[i] {
+		return 1
+	}
+	return -1
+}
+
+// gapUp is true when the real body of candle i is above that of candle j
+func (c *candles) gapUp(i int, j int) bool {
+	return c.bottom(i) > c.top(j)
+}
+
+// gapDown is true when the real body of candle i is below that of candle j
+func (c *candles) gapDown(i int, j int) bool {
+	return c.top(i) < c.bottom(j)
+}
+
+func (c *candles) candleRange(r CandleRange, i int) float64 {
+	switch r {
+	case RealBody:
+		return c.body(i)
+	case HighLow:
+		return c.inHigh[i] - c.inLow[i]
+	case Shadows:
+		return c.upperShadow(i) + c.lowerShadow(i)
+	}
+	return 0
+}
+
+// average is the threshold of a setting at candle i
+func (c *candles) average(s CandleSetting, i int) float64 {
+	v := 0.0
+	if s.Period == 0 {
+		v = c.candleRange(s.Range, i)
+	} else {
+		for k := i - s.Period; k < i; k++ {
+			v += c.candleRange(s.Range, k)
+		}
+		v /= float64(s.Period)
+	}
+	if s.Range == Shadows {
+		v /= 2
+	}
+	return s.Factor * v
+}
+
+// pattern sets the output of the candles from lookback on
+func pattern(inOpen, inHigh, inLow, inClose []float64, lookback int, match func(c *candles, i int) float64) []float64 {
+	outInteger := make([]float64, len(inClose))
+	c := &candles{inOpen, inHigh, inLow, inClose}
+	for i := lookback; i < len(inClose); i++ {
+		outInteger[i] = match(c, i)
+	}
+	return outInteger
+}
+
+// spanLookback is the lookback of a pattern spanning a number of candles that
+// reads the settings
+func spanLookback(bars int, settings ...CandleSetting) int {
+	period := 0
+	for _, s := range settings {
+		if s.Period > period {
+			period = s.Period
+		}
+	}
+	return period + bars - 1
+}
+
+// CandleLookback returns the lookback of a candlestick pattern, by its TA-Lib
+// name such as CDLHAMMER, or -1 when there is no such pattern
+func (s *CandleSettings) CandleLookback(name string) int {
+	switch name {
+	case "CDLDOJI":
+		return spanLookback(1, s.BodyDoji)
+	case "CDLDRAGONFLYDOJI", "CDLGRAVESTONEDOJI":
+		return spanLookback(1, s.BodyDoji, s.ShadowVeryShort)
+	case "CDLLONGLEGGEDDOJI":
+		return spanLookback(1, s.BodyDoji, s.ShadowLong)
+	case "CDLMARUBOZU":
+		return spanLookback(1, s.BodyLong, s.ShadowVeryShort)
+	case "CDLSPINNINGTOP":
+		return spanLookback(1, s.BodyShort)
+	case "CDLHAMMER", "CDLHANGINGMAN":
+		return spanLookback(2, s.BodyShort, s.ShadowLong, s.ShadowVeryShort, s.Near)
+	case "CDLINVERTEDHAMMER", "CDLSHOOTINGSTAR":
+		return spanLookback(2, s.BodyShort, s.ShadowLong, s.ShadowVeryShort)
+	case "CDLENGULFING":
+		return spanLookback(2)
+	case "CDLHARAMI":
+		return spanLookback(2, s.BodyShort, s.BodyLong)
+	case "CDLHARAMICROSS", "CDLDOJISTAR":
+		return spanLookback(2, s.BodyDoji, s.BodyLong)
+	case "CDLPIERCING", "CDLDARKCLOUDCOVER":
+		return spanLookback(2, s.BodyLong)
+	case "CDLMORNINGSTAR", "CDLEVENINGSTAR", "CDL3INSIDE":
+		return spanLookback(3, s.BodyShort, s.BodyLong)
+	case "CDLMORNINGDOJISTAR", "CDLEVENINGDOJISTAR":
+		return spanLookback(3, s.BodyDoji, s.BodyLong, s.BodyShort)
+	case "CDL3OUTSIDE":
+		return spanLookback(3)
+	case "CDL3WHITESOLDIERS":
+		return spanLookback(3, s.ShadowVeryShort, s.Near, s.Far, s.BodyShort)
+	case "CDL3BLACKCROWS":
+		return spanLookback(4, s.ShadowVeryShort)
+	}
+	return -1
+}
+
+// Doji - Doji: a candle of next to no real body
+func (s *CandleSettings) Doji(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLDOJI"), func(c *candles, i int) float64 {
+		if c.body(i) <= c.average(s.BodyDoji, i) {
+			return 100
+		}
+		return 0
+	})
+}
+
+// DragonflyDoji - Dragonfly Doji: a doji with a long lower shadow and next
+// to no upper shadow
+func (s *CandleSettings) DragonflyDoji(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLDRAGONFLYDOJI"), func(c *candles, i int) float64 {
+		if c.body(i) <= c.average(s.BodyDoji, i) &&
+			c.upperShadow(i) < c.average(s.ShadowVeryShort, i) &&
+			c.lowerShadow(i) > c.average(s.ShadowVeryShort, i) {
+			return 100
+		}
+		return 0
+	})
+}
+
+// GravestoneDoji - Gravestone Doji: a doji with a long upper shadow and next
+// to no lower shadow
+func (s *CandleSettings) GravestoneDoji(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLGRAVESTONEDOJI"), func(c *candles, i int) float64 {
+		if c.body(i) <= c.average(s.BodyDoji, i) &&
+			c.lowerShadow(i) < c.average(s.ShadowVeryShort, i) &&
+			c.upperShadow(i) > c.average(s.ShadowVeryShort, i) {
+			return 100
+		}
+		return 0
+	})
+}
+
+// LongLeggedDoji - Long Legged Doji: a doji with a long shadow
+func (s *CandleSettings) LongLeggedDoji(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLLONGLEGGEDDOJI"), func(c *candles, i int) float64 {
+		if c.body(i) <= c.average(s.BodyDoji, i) &&
+			(c.lowerShadow(i) > c.average(s.ShadowLong, i) || c.upperShadow(i) > c.average(s.ShadowLong, i)) {
+			return 100
+		}
+		return 0
+	})
+}
+
+// Marubozu - Marubozu: a long body with next to no shadows, +100 when white
+// and -100 when black
+func (s *CandleSettings) Marubozu(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLMARUBOZU"), func(c *candles, i int) float64 {
+		if c.body(i) > c.average(s.BodyLong, i) &&
+			c.upperShadow(i) < c.average(s.ShadowVeryShort, i) &&
+			c.lowerShadow(i) < c.average(s.ShadowVeryShort, i) {
+			return c.color(i) * 100
+		}
+		return 0
+	})
+}
+
+// SpinningTop - Spinning Top: a short body with shadows longer than it
+func (s *CandleSettings) SpinningTop(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLSPINNINGTOP"), func(c *candles, i int) float64 {
+		if c.body(i) < c.average(s.BodyShort, i) && c.upperShadow(i) > c.body(i) && c.lowerShadow(i) > c.body(i) {
+			return c.color(i) * 100
+		}
+		return 0
+	})
+}
+
+// Hammer - Hammer: a short body with a long lower shadow and next to no
+// upper shadow, at or below the low of the candle before
+func (s *CandleSettings) Hammer(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLHAMMER"), func(c *candles, i int) float64 {
+		if c.body(i) < c.average(s.BodyShort, i) &&
+			c.lowerShadow(i) > c.average(s.ShadowLong, i) &&
+			c.upperShadow(i) < c.average(s.ShadowVeryShort, i) &&
+			c.bottom(i) <= c.inLow[i-1]+c.average(s.Near, i-1) {
+			return 100
+		}
+		return 0
+	})
+}
+
+// HangingMan - Hanging Man: the hammer at or above the high of the candle
+// before
+func (s *CandleSettings) HangingMan(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLHANGINGMAN"), func(c *candles, i int) float64 {
+		if c.body(i) < c.average(s.BodyShort, i) &&
+			c.lowerShadow(i) > c.average(s.ShadowLong, i) &&
+			c.upperShadow(i) < c.average(s.ShadowVeryShort, i) &&
+			c.bottom(i) >= c.inHigh[i-1]-c.average(s.Near, i-1) {
+			return -100
+		}
+		return 0
+	})
+}
+
+// InvertedHammer - Inverted Hammer: a short body with a long upper shadow
+// and next to no lower shadow, gapping down from the body before
+func (s *CandleSettings) InvertedHammer(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLINVERTEDHAMMER"), func(c *candles, i int) float64 {
+		if c.body(i) < c.average(s.BodyShort, i) &&
+			c.upperShadow(i) > c.average(s.ShadowLong, i) &&
+			c.lowerShadow(i) < c.average(s.ShadowVeryShort, i) &&
+			c.gapDown(i, i-1) {
+			return 100
+		}
+		return 0
+	})
+}
+
+// ShootingStar - Shooting Star: the inverted hammer gapping up from the body
+// before
+func (s *CandleSettings) ShootingStar(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLSHOOTINGSTAR"), func(c *candles, i int) float64 {
+		if c.body(i) < c.average(s.BodyShort, i) &&
+			c.upperShadow(i) > c.average(s.ShadowLong, i) &&
+			c.lowerShadow(i) < c.average(s.ShadowVeryShort, i) &&
+			c.gapUp(i, i-1) {
+			return -100
+		}
+		return 0
+	})
+}
+
+// Engulfing - Engulfing Pattern: a body engulfing the body of the opposite
+// color before, +100 when white and -100 when black
+func (s *CandleSettings) Engulfing(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLENGULFING"), func(c *candles, i int) float64 {
+		if (c.color(i) == 1 && c.color(i-1) == -1 && c.inClose[i] > c.inOpen[i-1] && c.inOpen[i] < c.inClose[i-1]) ||
+			(c.color(i) == -1 && c.color(i-1) == 1 && c.inOpen[i] > c.inClose[i-1] && c.inClose[i] < c.inOpen[i-1]) {
+			return c.color(i) * 100
+		}
+		return 0
+	})
+}
+
+// Harami - Harami Pattern: a short body within the long body before, +100
+// after a black candle and -100 after a white one
+func (s *CandleSettings) Harami(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLHARAMI"), func(c *candles, i int) float64 {
+		if c.body(i-1) > c.average(s.BodyLong, i-1) && c.body(i) <= c.average(s.BodyShort, i) &&
+			c.top(i) < c.top(i-1) && c.bottom(i) > c.bottom(i-1) {
+			return -c.color(i-1) * 100
+		}
+		return 0
+	})
+}
+
+// HaramiCross - Harami Cross Pattern: the harami of a doji
+func (s *CandleSettings) HaramiCross(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLHARAMICROSS"), func(c *candles, i int) float64 {
+		if c.body(i-1) > c.average(s.BodyLong, i-1) && c.body(i) <= c.average(s.BodyDoji, i) &&
+			c.top(i) < c.top(i-1) && c.bottom(i) > c.bottom(i-1) {
+			return -c.color(i-1) * 100
+		}
+		return 0
+	})
+}
+
+// DojiStar - Doji Star: a doji gapping away from the long body before, -100
+// above a white candle and +100 below a black one
+func (s *CandleSettings) DojiStar(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLDOJISTAR"), func(c *candles, i int) float64 {
+		if c.body(i-1) > c.average(s.BodyLong, i-1) && c.body(i) <= c.average(s.BodyDoji, i) &&
+			((c.color(i-1) == 1 && c.gapUp(i, i-1)) || (c.color(i-1) == -1 && c.gapDown(i, i-1))) {
+			return -c.color(i-1) * 100
+		}
+		return 0
+	})
+}
+
+// Piercing - Piercing Pattern: a long white candle opening below the low of
+// the long black candle before and closing above its midpoint
+func (s *CandleSettings) Piercing(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLPIERCING"), func(c *candles, i int) float64 {
+		if c.color(i-1) == -1 && c.body(i-1) > c.average(s.BodyLong, i-1) &&
+			c.color(i) == 1 && c.body(i) > c.average(s.BodyLong, i) &&
+			c.inOpen[i] < c.inLow[i-1] && c.inClose[i] < c.inOpen[i-1] &&
+			c.inClose[i] > c.inClose[i-1]+c.body(i-1)*0.5 {
+			return 100
+		}
+		return 0
+	})
+}
+
+// DarkCloudCover - Dark Cloud Cover: a black candle opening above the high
+// of the long white candle before and closing into its body by the
+// penetration, 0.5 in TA-Lib
+func (s *CandleSettings) DarkCloudCover(inOpen, inHigh, inLow, inClose []float64, inPenetration float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLDARKCLOUDCOVER"), func(c *candles, i int) float64 {
+		if c.color(i-1) == 1 && c.body(i-1) > c.average(s.BodyLong, i-1) &&
+			c.color(i) == -1 && c.inOpen[i] > c.inHigh[i-1] && c.inClose[i] > c.inOpen[i-1] &&
+			c.inClose[i] < c.inClose[i-1]-c.body(i-1)*inPenetration {
+			return -100
+		}
+		return 0
+	})
+}
+
+// MorningStar - Morning Star: a long black candle, a short body gapping down
+// and a white candle closing into the first body by the penetration, 0.3 in
+// TA-Lib
+func (s *CandleSettings) MorningStar(inOpen, inHigh, inLow, inClose []float64, inPenetration float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLMORNINGSTAR"), func(c *candles, i int) float64 {
+		if c.body(i-2) > c.average(s.BodyLong, i-2) && c.color(i-2) == -1 &&
+			c.body(i-1) <= c.average(s.BodyShort, i-1) && c.gapDown(i-1, i-2) &&
+			c.body(i) > c.average(s.BodyShort, i) && c.color(i) == 1 &&
+			c.inClose[i] > c.inClose[i-2]+c.body(i-2)*inPenetration {
+			return 100
+		}
+		return 0
+	})
+}
+
+// EveningStar - Evening Star: a long white candle, a short body gapping up
+// and a black candle closing into the first body by the penetration, 0.3 in
+// TA-Lib
+func (s *CandleSettings) EveningStar(inOpen, inHigh, inLow, inClose []float64, inPenetration float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLEVENINGSTAR"), func(c *candles, i int) float64 {
+		if c.body(i-2) > c.average(s.BodyLong, i-2) && c.color(i-2) == 1 &&
+			c.body(i-1) <= c.average(s.BodyShort, i-1) && c.gapUp(i-1, i-2) &&
+			c.body(i) > c.average(s.BodyShort, i) && c.color(i) == -1 &&
+			c.inClose[i] < c.inClose[i-2]-c.body(i-2)*inPenetration {
+			return -100
+		}
+		return 0
+	})
+}
+
+// MorningDojiStar - Morning Doji Star: the morning star of a doji
+func (s *CandleSettings) MorningDojiStar(inOpen, inHigh, inLow, inClose []float64, inPenetration float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLMORNINGDOJISTAR"), func(c *candles, i int) float64 {
+		if c.body(i-2) > c.average(s.BodyLong, i-2) && c.color(i-2) == -1 &&
+			c.body(i-1) <= c.average(s.BodyDoji, i-1) && c.gapDown(i-1, i-2) &&
+			c.body(i) > c.average(s.BodyShort, i) && c.color(i) == 1 &&
+			c.inClose[i] > c.inClose[i-2]+c.body(i-2)*inPenetration {
+			return 100
+		}
+		return 0
+	})
+}
+
+// EveningDojiStar - Evening Doji Star: the evening star of a doji
+func (s *CandleSettings) EveningDojiStar(inOpen, inHigh, inLow, inClose []float64, inPenetration float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDLEVENINGDOJISTAR"), func(c *candles, i int) float64 {
+		if c.body(i-2) > c.average(s.BodyLong, i-2) && c.color(i-2) == 1 &&
+			c.body(i-1) <= c.average(s.BodyDoji, i-1) && c.gapUp(i-1, i-2) &&
+			c.body(i) > c.average(s.BodyShort, i) && c.color(i) == -1 &&
+			c.inClose[i] < c.inClose[i-2]-c.body(i-2)*inPenetration {
+			return -100
+		}
+		return 0
+	})
+}
+
+// ThreeInside - Three Inside Up/Down: a harami confirmed by a third candle
+// closing beyond the open of the first, +100 up and -100 down
+func (s *CandleSettings) ThreeInside(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDL3INSIDE"), func(c *candles, i int) float64 {
+		if c.body(i-2) > c.average(s.BodyLong, i-2) && c.body(i-1) <= c.average(s.BodyShort, i-1) &&
+			c.top(i-1) < c.top(i-2) && c.bottom(i-1) > c.bottom(i-2) &&
+			((c.color(i-2) == 1 && c.color(i) == -1 && c.inClose[i] < c.inOpen[i-2]) ||
+				(c.color(i-2) == -1 && c.color(i) == 1 && c.inClose[i] > c.inOpen[i-2])) {
+			return -c.color(i-2) * 100
+		}
+		return 0
+	})
+}
+
+// ThreeOutside - Three Outside Up/Down: an engulfing pattern confirmed by a
+// third candle closing beyond the second, +100 up and -100 down
+func (s *CandleSettings) ThreeOutside(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDL3OUTSIDE"), func(c *candles, i int) float64 {
+		if (c.color(i-1) == 1 && c.color(i-2) == -1 && c.inClose[i-1] > c.inOpen[i-2] && c.inOpen[i-1] < c.inClose[i-2] &&
+			c.inClose[i] > c.inClose[i-1]) ||
+			(c.color(i-1) == -1 && c.color(i-2) == 1 && c.inOpen[i-1] > c.inClose[i-2] && c.inClose[i-1] < c.inOpen[i-2] &&
+				c.inClose[i] < c.inClose[i-1]) {
+			return c.color(i-1) * 100
+		}
+		return 0
+	})
+}
+
+// ThreeWhiteSoldiers - Three Advancing White Soldiers: three white candles of
+// rising closes and next to no upper shadows, each opening within or near
+// the body before and none much shorter than the one before
+func (s *CandleSettings) ThreeWhiteSoldiers(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDL3WHITESOLDIERS"), func(c *candles, i int) float64 {
+		if c.color(i-2) == 1 && c.upperShadow(i-2) < c.average(s.ShadowVeryShort, i-2) &&
+			c.color(i-1) == 1 && c.upperShadow(i-1) < c.average(s.ShadowVeryShort, i-1) &&
+			c.color(i) == 1 && c.upperShadow(i) < c.average(s.ShadowVeryShort, i) &&
+			c.inClose[i] > c.inClose[i-1] && c.inClose[i-1] > c.inClose[i-2] &&
+			c.inOpen[i-1] > c.inOpen[i-2] && c.inOpen[i-1] <= c.inClose[i-2]+c.average(s.Near, i-2) &&
+			c.inOpen[i] > c.inOpen[i-1] && c.inOpen[i] <= c.inClose[i-1]+c.average(s.Near, i-1) &&
+			c.body(i-1) > c.body(i-2)-c.average(s.Far, i-2) &&
+			c.body(i) > c.body(i-1)-c.average(s.Far, i-1) &&
+			c.body(i) > c.average(s.BodyShort, i) {
+			return 100
+		}
+		return 0
+	})
+}
+
+// ThreeBlackCrows - Three Black Crows: after a white candle, three black
+// candles of falling closes and next to no lower shadows, each opening
+// within the body before
+func (s *CandleSettings) ThreeBlackCrows(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return pattern(inOpen, inHigh, inLow, inClose, s.CandleLookback("CDL3BLACKCROWS"), func(c *candles, i int) float64 {
+		if c.color(i-3) == 1 &&
+			c.color(i-2) == -1 && c.lowerShadow(i-2) < c.average(s.ShadowVeryShort, i-2) &&
+			c.color(i-1) == -1 && c.lowerShadow(i-1) < c.average(s.ShadowVeryShort, i-1) &&
+			c.color(i) == -1 && c.lowerShadow(i) < c.average(s.ShadowVeryShort, i) &&
+			c.inOpen[i-1] < c.inOpen[i-2] && c.inOpen[i-1] > c.inClose[i-2] &&
+			c.inOpen[i] < c.inOpen[i-1] && c.inOpen[i] > c.inClose[i-1] &&
+			c.inHigh[i-3] > c.inClose[i-2] &&
+			c.inClose[i-2] > c.inClose[i-1] && c.inClose[i-1] > c.inClose[i] {
+			return -100
+		}
+		return 0
+	})
+}
+
+// CdlDoji - Doji, on DefaultCandleSettings
+func CdlDoji(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.Doji(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlDragonflyDoji - Dragonfly Doji, on DefaultCandleSettings
+func CdlDragonflyDoji(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.DragonflyDoji(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlGravestoneDoji - Gravestone Doji, on DefaultCandleSettings
+func CdlGravestoneDoji(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.GravestoneDoji(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlLongLeggedDoji - Long Legged Doji, on DefaultCandleSettings
+func CdlLongLeggedDoji(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.LongLeggedDoji(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlMarubozu - Marubozu, on DefaultCandleSettings
+func CdlMarubozu(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.Marubozu(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlSpinningTop - Spinning Top, on DefaultCandleSettings
+func CdlSpinningTop(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.SpinningTop(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlHammer - Hammer, on DefaultCandleSettings
+func CdlHammer(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.Hammer(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlHangingMan - Hanging Man, on DefaultCandleSettings
+func CdlHangingMan(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.HangingMan(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlInvertedHammer - Inverted Hammer, on DefaultCandleSettings
+func CdlInvertedHammer(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.InvertedHammer(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlShootingStar - Shooting Star, on DefaultCandleSettings
+func CdlShootingStar(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.ShootingStar(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlEngulfing - Engulfing Pattern, on DefaultCandleSettings
+func CdlEngulfing(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.Engulfing(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlHarami - Harami Pattern, on DefaultCandleSettings
+func CdlHarami(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.Harami(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlHaramiCross - Harami Cross Pattern, on DefaultCandleSettings
+func CdlHaramiCross(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.HaramiCross(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlDojiStar - Doji Star, on DefaultCandleSettings
+func CdlDojiStar(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.DojiStar(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlPiercing - Piercing Pattern, on DefaultCandleSettings
+func CdlPiercing(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.Piercing(inOpen, inHigh, inLow, inClose)
+}
+
+// CdlDarkCloudCover - Dark Cloud Cover, on DefaultCandleSettings
+func CdlDarkCloudCover(inOpen, inHigh, inLow, inClose []float64, inPenetration float64) []float64 {
+	return DefaultCandleSettings.DarkCloudCover(inOpen, inHigh, inLow, inClose, inPenetration)
+}
+
+// CdlMorningStar - Morning Star, on DefaultCandleSettings
+func CdlMorningStar(inOpen, inHigh, inLow, inClose []float64, inPenetration float64) []float64 {
+	return DefaultCandleSettings.MorningStar(inOpen, inHigh, inLow, inClose, inPenetration)
+}
+
+// CdlEveningStar - Evening Star, on DefaultCandleSettings
+func CdlEveningStar(inOpen, inHigh, inLow, inClose []float64, inPenetration float64) []float64 {
+	return DefaultCandleSettings.EveningStar(inOpen, inHigh, inLow, inClose, inPenetration)
+}
+
+// CdlMorningDojiStar - Morning Doji Star, on DefaultCandleSettings
+func CdlMorningDojiStar(inOpen, inHigh, inLow, inClose []float64, inPenetration float64) []float64 {
+	return DefaultCandleSettings.MorningDojiStar(inOpen, inHigh, inLow, inClose, inPenetration)
+}
+
+// CdlEveningDojiStar - Evening Doji Star, on DefaultCandleSettings
+func CdlEveningDojiStar(inOpen, inHigh, inLow, inClose []float64, inPenetration float64) []float64 {
+	return DefaultCandleSettings.EveningDojiStar(inOpen, inHigh, inLow, inClose, inPenetration)
+}
+
+// Cdl3Inside - Three Inside Up/Down, on DefaultCandleSettings
+func Cdl3Inside(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.ThreeInside(inOpen, inHigh, inLow, inClose)
+}
+
+// Cdl3Outside - Three Outside Up/Down, on DefaultCandleSettings
+func Cdl3Outside(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.ThreeOutside(inOpen, inHigh, inLow, inClose)
+}
+
+// Cdl3WhiteSoldiers - Three Advancing White Soldiers, on DefaultCandleSettings
+func Cdl3WhiteSoldiers(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.ThreeWhiteSoldiers(inOpen, inHigh, inLow, inClose)
+}
+
+// Cdl3BlackCrows - Three Black Crows, on DefaultCandleSettings
+func Cdl3BlackCrows(inOpen, inHigh, inLow, inClose []float64) []float64 {
+	return DefaultCandleSettings.ThreeBlackCrows(inOpen, inHigh, inLow, inClose)
+}
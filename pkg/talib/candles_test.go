@@ -0,0 +1,72 @@
+package talib
+
+import "testing"
+
+// bars are candles of open, high, low and close
+type bars [][4]float64
+
+// series returns twelve white candles of body 1 and range 2, followed by the
+// candles given
+func (b bars) series() (o, h, l, c []float64) {
+	for i := 0; i < 12; i++ {
+		o, h, l, c = append(o, 100), append(h, 101.5), append(l, 99.5), append(c, 101)
+	}
+	for _, bar := range b {
+		o, h, l, c = append(o, bar[0]), append(h, bar[1]), append(l, bar[2]), append(c, bar[3])
+	}
+	return
+}
+
+func TestCandlePatterns(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		bars bars
+		f    func(o, h, l, c []float64) []float64
+		want float64
+	}{
+		{"doji", bars{{100, 101, 99, 100.05}}, CdlDoji, 100},
+		{"dragonfly doji", bars{{100, 100.05, 98, 100}}, CdlDragonflyDoji, 100},
+		{"gravestone doji", bars{{100, 102, 99.95, 100}}, CdlGravestoneDoji, 100},
+		{"white marubozu", bars{{100, 103, 100, 103}}, CdlMarubozu, 100},
+		{"black marubozu", bars{{103, 103, 100, 100}}, CdlMarubozu, -100},
+		{"hammer", bars{{99.8, 100.1, 98, 100.1}}, CdlHammer, 100},
+		{"hanging man", bars{{101.5, 101.8, 100, 101.8}}, CdlHangingMan, -100},
+		{"shooting star", bars{{102, 104, 101.65, 101.7}}, CdlShootingStar, -100},
+		{"inverted hammer", bars{{99.5, 101.5, 99.45, 99.8}}, CdlInvertedHammer, 100},
+		{"bullish engulfing", bars{{101, 101.2, 99.8, 100}, {99.8, 101.5, 99.7, 101.3}}, CdlEngulfing, 100},
+		{"bearish engulfing", bars{{100, 101.2, 99.8, 101}, {101.2, 101.3, 99.5, 99.8}}, CdlEngulfing, -100},
+		{"bullish harami", bars{{103, 103.2, 99.8, 100}, {101, 101.8, 100.8, 101.5}}, CdlHarami, 100},
+		{"bearish harami", bars{{100, 103.2, 99.8, 103}, {102, 102.2, 101.2, 101.5}}, CdlHarami, -100},
+		{"piercing", bars{{103, 103.2, 100.8, 101}, {100.5, 102.5, 100.4, 102.4}}, CdlPiercing, 100},
+		{"morning star", bars{{103, 103.2, 99.8, 100}, {99.5, 99.9, 99, 99.4}, {99.8, 102.5, 99.7, 102.2}}, func(o, h, l, c []float64) []float64 {
+			return CdlMorningStar(o, h, l, c, 0.3)
+		}, 100},
+		{"evening star", bars{{100, 103.2, 99.8, 103}, {103.5, 104, 103.3, 103.6}, {103.2, 103.3, 100.5, 100.8}}, func(o, h, l, c []float64) []float64 {
+			return CdlEveningStar(o, h, l, c, 0.3)
+		}, -100},
+		{"three white soldiers", bars{{100, 101.55, 99.9, 101.5}, {101.4, 103.05, 101.3, 103}, {102.9, 104.65, 102.8, 104.6}}, Cdl3WhiteSoldiers, 100},
+		{"three black crows", bars{{101.2, 103.1, 101.1, 103}, {102.9, 103, 101.45, 101.5}, {101.6, 101.7, 99.95, 100}, {100.2, 100.3, 98.45, 98.5}}, Cdl3BlackCrows, -100},
+		{"three outside up", bars{{101, 101.2, 99.8, 100}, {99.8, 101.5, 99.7, 101.3}, {101.3, 102.2, 101.2, 102}}, Cdl3Outside, 100},
+	} {
+		o, h, l, c := test.bars.series()
+		out := test.f(o, h, l, c)
+		if got := out[len(out)-1]; got != test.want {
+			t.Errorf("%s: %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCandleSettings(t *testing.T) {
+	o, h, l, c := bars{{100, 101, 99, 100.3}}.series()
+	if out := CdlDoji(o, h, l, c); out[12] != 0 {
+		t.Errorf("doji of body 0.3 in ranges of 2: %v", out[12])
+	}
+	loose := DefaultCandleSettings
+	loose.BodyDoji.Factor = 0.2
+	if out := loose.Doji(o, h, l, c); out[12] != 100 {
+		t.Errorf("doji of body 0.3 at factor 0.2: %v", out[12])
+	}
+	if n := DefaultCandleSettings.CandleLookback("CDLMORNINGSTAR"); n != 12 {
+		t.Errorf("morning star lookback %d", n)
+	}
+}
@@ -0,0 +1,384 @@
+package talib
+
+// The lookback of a function is the number of leading values it leaves at 0
+// before every one of its outputs is valid, as WarmUpNaN takes it. A few
+// outputs are set sooner, such as the bands of BBands over a slower moving
+// average. Functions without a lookback function here, such as the math
+// and price transforms, have a lookback of 0; the candlestick patterns have
+// CandleLookback.
+
+func maxInt(values ...int) int {
+	max := values[0]
+	for _, v := range values[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+/* Overlap Studies */
+
+// BBandsLookback is the lookback of BBands
+func BBandsLookback(inTimePeriod int, inMAType MaType) int {
+	return maxInt(MaLookback(inTimePeriod, inMAType), inTimePeriod-1)
+}
+
+// DemaLookback is the lookback of Dema
+func DemaLookback(inTimePeriod int) int {
+	return 2 * (inTimePeriod - 1)
+}
+
+// EmaLookback is the lookback of Ema
+func EmaLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// HtTrendlineLookback is the lookback of HtTrendline
+func HtTrendlineLookback() int {
+	return 63
+}
+
+// KamaLookback is the lookback of Kama
+func KamaLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// MaLookback is the lookback of Ma
+func MaLookback(inTimePeriod int, inMAType MaType) int {
+	if inTimePeriod == 1 {
+		return 0
+	}
+	switch inMAType {
+	case DEMA:
+		return DemaLookback(inTimePeriod)
+	case TEMA:
+		return TemaLookback(inTimePeriod)
+	case KAMA:
+		return KamaLookback(inTimePeriod)
+	case MAMA:
+		return MamaLookback()
+	case T3MA:
+		return T3Lookback(inTimePeriod)
+	}
+	return inTimePeriod - 1
+}
+
+// MamaLookback is the lookback of Mama
+func MamaLookback() int {
+	return 32
+}
+
+// MidPointLookback is the lookback of MidPoint
+func MidPointLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// MidPriceLookback is the lookback of MidPrice
+func MidPriceLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// SarLookback is the lookback of Sar and SarExt
+func SarLookback() int {
+	return 1
+}
+
+// SmaLookback is the lookback of Sma
+func SmaLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// T3Lookback is the lookback of T3
+func T3Lookback(inTimePeriod int) int {
+	return 6 * (inTimePeriod - 1)
+}
+
+// TemaLookback is the lookback of Tema
+func TemaLookback(inTimePeriod int) int {
+	return 3 * (inTimePeriod - 1)
+}
+
+// TrimaLookback is the lookback of Trima
+func TrimaLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// WmaLookback is the lookback of Wma
+func WmaLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+/* Overlap Studies beyond TA-Lib */
+
+// SupertrendLookback is the lookback of Supertrend
+func SupertrendLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// IchimokuLookback is the lookback of Ichimoku, until both leading spans
+// are drawn; the other lines start before
+func IchimokuLookback(inConversionPeriod int, inBasePeriod int, inSpanBPeriod int, inDisplacement int) int {
+	return maxInt(inConversionPeriod, inBasePeriod, inSpanBPeriod) - 1 + inDisplacement
+}
+
+// KeltnerLookback is the lookback of Keltner
+func KeltnerLookback(inTimePeriod int, inAtrPeriod int) int {
+	return maxInt(inTimePeriod-1, inAtrPeriod)
+}
+
+// DonchianLookback is the lookback of Donchian
+func DonchianLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// VwapLookback is the lookback of Vwap
+func VwapLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// AnchoredVwapLookback is the lookback of AnchoredVwap
+func AnchoredVwapLookback(inAnchor int) int {
+	return inAnchor
+}
+
+// ChandelierExitLookback is the lookback of ChandelierExit
+func ChandelierExitLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// PivotsLookback is the lookback of the pivot points
+func PivotsLookback() int {
+	return 1
+}
+
+/* Momentum Indicators */
+
+// AdxLookback is the lookback of Adx
+func AdxLookback(inTimePeriod int) int {
+	return 2*inTimePeriod - 1
+}
+
+// AdxRLookback is the lookback of AdxR
+func AdxRLookback(inTimePeriod int) int {
+	return 3*inTimePeriod - 2
+}
+
+// ApoLookback is the lookback of Apo
+func ApoLookback(inFastPeriod int, inSlowPeriod int, inMAType MaType) int {
+	return MaLookback(maxInt(inFastPeriod, inSlowPeriod), inMAType)
+}
+
+// AroonLookback is the lookback of Aroon
+func AroonLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// AroonOscLookback is the lookback of AroonOsc
+func AroonOscLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// CciLookback is the lookback of Cci
+func CciLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// CmoLookback is the lookback of Cmo
+func CmoLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// DxLookback is the lookback of Dx
+func DxLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// MacdLookback is the lookback of Macd. The port sets macd and its signal
+// a session before the histogram.
+func MacdLookback(inFastPeriod int, inSlowPeriod int, inSignalPeriod int) int {
+	return maxInt(inFastPeriod, inSlowPeriod) - 1 + inSignalPeriod - 1
+}
+
+// MacdExtLookback is the lookback of MacdExt. The port starts the outputs
+// by the periods whatever the types, before slower averages are valid.
+func MacdExtLookback(inFastPeriod int, inFastMAType MaType, inSlowPeriod int, inSlowMAType MaType, inSignalPeriod int, inSignalMAType MaType) int {
+	start := maxInt(inFastPeriod, inSlowPeriod) - 1 + inSignalPeriod - 1
+	valid := maxInt(MaLookback(inFastPeriod, inFastMAType), MaLookback(inSlowPeriod, inSlowMAType)) +
+		MaLookback(inSignalPeriod, inSignalMAType)
+	return maxInt(start, valid)
+}
+
+// MacdFixLookback is the lookback of MacdFix
+func MacdFixLookback(inSignalPeriod int) int {
+	return MacdLookback(12, 26, inSignalPeriod)
+}
+
+// MfiLookback is the lookback of Mfi
+func MfiLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// MinusDILookback is the lookback of MinusDI
+func MinusDILookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// MinusDMLookback is the lookback of MinusDM
+func MinusDMLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// MomLookback is the lookback of Mom
+func MomLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// PlusDILookback is the lookback of PlusDI
+func PlusDILookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// PlusDMLookback is the lookback of PlusDM
+func PlusDMLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// PpoLookback is the lookback of Ppo
+func PpoLookback(inFastPeriod int, inSlowPeriod int, inMAType MaType) int {
+	return ApoLookback(inFastPeriod, inSlowPeriod, inMAType)
+}
+
+// RocLookback is the lookback of Roc, Rocp, Rocr and Rocr100
+func RocLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// RsiLookback is the lookback of Rsi
+func RsiLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// StochLookback is the lookback of Stoch
+func StochLookback(inFastKPeriod int, inSlowKPeriod int, inSlowKMAType MaType, inSlowDPeriod int, inSlowDMAType MaType) int {
+	return inFastKPeriod - 1 + MaLookback(inSlowKPeriod, inSlowKMAType) + MaLookback(inSlowDPeriod, inSlowDMAType)
+}
+
+// StochFLookback is the lookback of StochF
+func StochFLookback(inFastKPeriod int, inFastDPeriod int, inFastDMAType MaType) int {
+	return inFastKPeriod - 1 + MaLookback(inFastDPeriod, inFastDMAType)
+}
+
+// StochRsiLookback is the lookback of StochRsi
+func StochRsiLookback(inTimePeriod int, inFastKPeriod int, inFastDPeriod int, inFastDMAType MaType) int {
+	return RsiLookback(inTimePeriod) + StochFLookback(inFastKPeriod, inFastDPeriod, inFastDMAType)
+}
+
+// TrixLookback is the lookback of Trix
+func TrixLookback(inTimePeriod int) int {
+	return 3*(inTimePeriod-1) + 1
+}
+
+// UltOscLookback is the lookback of UltOsc
+func UltOscLookback(inTimePeriod1 int, inTimePeriod2 int, inTimePeriod3 int) int {
+	return maxInt(inTimePeriod1, inTimePeriod2, inTimePeriod3)
+}
+
+// WillRLookback is the lookback of WillR
+func WillRLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+/* Volume Indicators */
+
+// AdOscLookback is the lookback of AdOsc
+func AdOscLookback(inFastPeriod int, inSlowPeriod int) int {
+	return maxInt(inFastPeriod, inSlowPeriod) - 1
+}
+
+/* Volatility Indicators */
+
+// AtrLookback is the lookback of Atr
+func AtrLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// NatrLookback is the lookback of Natr
+func NatrLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// TRangeLookback is the lookback of TRange
+func TRangeLookback() int {
+	return 1
+}
+
+/* Price Transform */
+
+// HeikinashiCandlesLookback is the lookback of HeikinashiCandles
+func HeikinashiCandlesLookback() int {
+	return 1
+}
+
+/* Cycle Indicators */
+
+// HtDcPeriodLookback is the lookback of HtDcPeriod
+func HtDcPeriodLookback() int {
+	return 32
+}
+
+// HtDcPhaseLookback is the lookback of HtDcPhase
+func HtDcPhaseLookback() int {
+	return 63
+}
+
+// HtPhasorLookback is the lookback of HtPhasor
+func HtPhasorLookback() int {
+	return 32
+}
+
+// HtSineLookback is the lookback of HtSine
+func HtSineLookback() int {
+	return 63
+}
+
+// HtTrendModeLookback is the lookback of HtTrendMode
+func HtTrendModeLookback() int {
+	return 63
+}
+
+/* Statistic Functions */
+
+// BetaLookback is the lookback of Beta
+func BetaLookback(inTimePeriod int) int {
+	return inTimePeriod
+}
+
+// CorrelLookback is the lookback of Correl
+func CorrelLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// LinearRegLookback is the lookback of LinearReg, LinearRegAngle,
+// LinearRegIntercept, LinearRegSlope and Tsf
+func LinearRegLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// StdDevLookback is the lookback of StdDev
+func StdDevLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+// VarLookback is the lookback of Var
+func VarLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
+
+/* Math Operator Functions */
+
+// MaxLookback is the lookback of Max, MaxIndex, Min, MinIndex, MinMax,
+// MinMaxIndex and Sum
+func MaxLookback(inTimePeriod int) int {
+	return inTimePeriod - 1
+}
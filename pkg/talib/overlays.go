@@ -0,0 +1,257 @@
+package talib
+
+// Supertrend - Supertrend (lookback=inTimePeriod)
+//
+// Returns the supertrend line and the direction of the trend, 1 up with the
+// line below the prices and -1 down with the line above them. The bands are
+// the median price off by inMultiplier times the average true range, the
+// lower one only rising and the upper one only falling while the trend
+// lasts. The trend starts down, as on TradingView.
+func Supertrend(inHigh []float64, inLow []float64, inClose []float64, inTimePeriod int, inMultiplier float64) ([]float64, []float64) {
+	outSupertrend := make([]float64, len(inClose))
+	outDirection := make([]float64, len(inClose))
+	atr := Atr(inHigh, inLow, inClose, inTimePeriod)
+	upper, lower := 0.0, 0.0
+	for i := inTimePeriod; i < len(inClose); i++ {
+		median := (inHigh[i] + inLow[i]) / 2
+		basicUpper := median + inMultiplier*atr[i]
+		basicLower := median - inMultiplier*atr[i]
+		if i == inTimePeriod {
+			upper, lower = basicUpper, basicLower
+			outDirection[i] = -1
+		} else {
+			if basicUpper < upper || inClose[i-1] > upper {
+				upper = basicUpper
+			}
+			if basicLower > lower || inClose[i-1] < lower {
+				lower = basicLower
+			}
+			switch {
+			case outDirection[i-1] < 0 && inClose[i] > upper:
+				outDirection[i] = 1
+			case outDirection[i-1] > 0 && inClose[i] < lower:
+				outDirection[i] = -1
+			default:
+				outDirection[i] = outDirection[i-1]
+			}
+		}
+		if outDirection[i] > 0 {
+			outSupertrend[i] = lower
+		} else {
+			outSupertrend[i] = upper
+		}
+	}
+	return outSupertrend, outDirection
+}
+
+// Ichimoku - Ichimoku Kinko Hyo
+//
+// Returns the conversion line (tenkan-sen), the base line (kijun-sen), the
+// leading spans A and B (senkou span) and the lagging span (chikou span),
+// each line at the session it is drawn on. The leading spans are computed
+// inDisplacement sessions before, so their lookback is that of their lines
+// plus inDisplacement. The lagging span is drawn inDisplacement sessions
+// back on a chart, which would show each session a later close; here it
+// is the close inDisplacement sessions before, set on the session it is
+// drawn against (lookback=inDisplacement).
+func Ichimoku(inHigh []float64, inLow []float64, inClose []float64, inConversionPeriod int, inBasePeriod int, inSpanBPeriod int, inDisplacement int) ([]float64, []float64, []float64, []float64, []float64) {
+	outConversion := midRange(inHigh, inLow, inConversionPeriod)
+	outBase := midRange(inHigh, inLow, inBasePeriod)
+	spanB := midRange(inHigh, inLow, inSpanBPeriod)
+	outSpanA := make([]float64, len(inClose))
+	outSpanB := make([]float64, len(inClose))
+	outLagging := make([]float64, len(inClose))
+
+	lookbackA := inBasePeriod - 1
+	if inConversionPeriod > inBasePeriod {
+		lookbackA = inConversionPeriod - 1
+	}
+	for i := lookbackA + inDisplacement; i < len(inClose); i++ {
+		outSpanA[i] = (outConversion[i-inDisplacement] + outBase[i-inDisplacement]) / 2
+	}
+	for i := inSpanBPeriod - 1 + inDisplacement; i < len(inClose); i++ {
+		outSpanB[i] = spanB[i-inDisplacement]
+	}
+	for i := inDisplacement; i < len(inClose); i++ {
+		outLagging[i] = inClose[i-inDisplacement]
+	}
+	return outConversion, outBase, outSpanA, outSpanB, outLagging
+}
+
+// midRange is the middle of the highest high and the lowest low of the
+// period (lookback=inTimePeriod-1)
+func midRange(inHigh []float64, inLow []float64, inTimePeriod int) []float64 {
+	outReal := Max(inHigh, inTimePeriod)
+	lowest := Min(inLow, inTimePeriod)
+	for i := inTimePeriod - 1; i < len(outReal); i++ {
+		outReal[i] = (outReal[i] + lowest[i]) / 2
+	}
+	return outReal
+}
+
+// Keltner - Keltner Channels
+//
+// Returns the upper, middle and lower bands: the exponential moving average
+// of the closes, off by inMultiplier times the average true range of
+// inAtrPeriod. The lookback is the larger of inTimePeriod-1 and inAtrPeriod.
+func Keltner(inHigh []float64, inLow []float64, inClose []float64, inTimePeriod int, inMultiplier float64, inAtrPeriod int) ([]float64, []float64, []float64) {
+	outRealUpperBand := make([]float64, len(inClose))
+	outRealMiddleBand := make([]float64, len(inClose))
+	outRealLowerBand := make([]float64, len(inClose))
+	ema := Ema(inClose, inTimePeriod)
+	atr := Atr(inHigh, inLow, inClose, inAtrPeriod)
+	lookbackTotal := inTimePeriod - 1
+	if inAtrPeriod > lookbackTotal {
+		lookbackTotal = inAtrPeriod
+	}
+	for i := lookbackTotal; i < len(inClose); i++ {
+		outRealMiddleBand[i] = ema[i]
+		outRealUpperBand[i] = ema[i] + inMultiplier*atr[i]
+		outRealLowerBand[i] = ema[i] - inMultiplier*atr[i]
+	}
+	return outRealUpperBand, outRealMiddleBand, outRealLowerBand
+}
+
+// Donchian - Donchian Channels (lookback=inTimePeriod-1)
+//
+// Returns the upper, middle and lower bands: the highest high, the middle
+// and the lowest low of the period.
+func Donchian(inHigh []float64, inLow []float64, inTimePeriod int) ([]float64, []float64, []float64) {
+	outRealUpperBand := Max(inHigh, inTimePeriod)
+	outRealLowerBand := Min(inLow, inTimePeriod)
+	outRealMiddleBand := make([]float64, len(inHigh))
+	for i := inTimePeriod - 1; i < len(inHigh); i++ {
+		outRealMiddleBand[i] = (outRealUpperBand[i] + outRealLowerBand[i]) / 2
+	}
+	return outRealUpperBand, outRealMiddleBand, outRealLowerBand
+}
+
+// Vwap - Rolling Volume Weighted Average Price (lookback=inTimePeriod-1)
+//
+// The typical price of the period weighted by volume, or the typical price
+// of the session when no volume traded in the period.
+func Vwap(inHigh []float64, inLow []float64, inClose []float64, inVolume []float64, inTimePeriod int) []float64 {
+	outReal := make([]float64, len(inClose))
+	typical := TypPrice(inHigh, inLow, inClose)
+	value, volume := 0.0, 0.0
+	for i := range inClose {
+		value += typical[i] * inVolume[i]
+		volume += inVolume[i]
+		if i >= inTimePeriod {
+			value -= typical[i-inTimePeriod] * inVolume[i-inTimePeriod]
+			volume -= inVolume[i-inTimePeriod]
+		}
+		if i >= inTimePeriod-1 {
+			outReal[i] = vwap(value, volume, typical[i])
+		}
+	}
+	return outReal
+}
+
+// AnchoredVwap - Anchored Volume Weighted Average Price (lookback=inAnchor)
+//
+// The typical price weighted by volume since the session of index inAnchor,
+// such as that of a breakout or of results.
+func AnchoredVwap(inHigh []float64, inLow []float64, inClose []float64, inVolume []float64, inAnchor int) []float64 {
+	outReal := make([]float64, len(inClose))
+	typical := TypPrice(inHigh, inLow, inClose)
+	value, volume := 0.0, 0.0
+	for i := inAnchor; i < len(inClose); i++ {
+		value += typical[i] * inVolume[i]
+		volume += inVolume[i]
+		outReal[i] = vwap(value, volume, typical[i])
+	}
+	return outReal
+}
+
+func vwap(value float64, volume float64, typical float64) float64 {
+	if volume <= 0 {
+		return typical
+	}
+	return value / volume
+}
+
+// ChandelierExit - Chandelier Exit (lookback=inTimePeriod)
+//
+// Returns the exits of long and short positions: inMultiplier times the
+// average true range below the highest high of the period, and above its
+// lowest low.
+func ChandelierExit(inHigh []float64, inLow []float64, inClose []float64, inTimePeriod int, inMultiplier float64) ([]float64, []float64) {
+	outLong := make([]float64, len(inClose))
+	outShort := make([]float64, len(inClose))
+	atr := Atr(inHigh, inLow, inClose, inTimePeriod)
+	highest := Max(inHigh, inTimePeriod)
+	lowest := Min(inLow, inTimePeriod)
+	for i := inTimePeriod; i < len(inClose); i++ {
+		outLong[i] = highest[i] - inMultiplier*atr[i]
+		outShort[i] = lowest[i] + inMultiplier*atr[i]
+	}
+	return outLong, outShort
+}
+
+// Pivots are the pivot point of each session and its resistance and
+// support levels, R1 and S1 first, from the session before (lookback=1).
+// On daily quotes they are the levels of the day; resample the quotes for
+// weekly or monthly ones.
+type Pivots struct {
+	Pivot      []float64
+	Resistance [][]float64
+	Support    [][]float64
+}
+
+func newPivots(n int, levels int) *Pivots {
+	p := &Pivots{Pivot: make([]float64, n), Resistance: make([][]float64, levels), Support: make([][]float64, levels)}
+	for k := 0; k < levels; k++ {
+		p.Resistance[k] = make([]float64, n)
+		p.Support[k] = make([]float64, n)
+	}
+	return p
+}
+
+// ClassicPivots - Classic (floor) pivot points, with three levels
+func ClassicPivots(inHigh []float64, inLow []float64, inClose []float64) *Pivots {
+	p := newPivots(len(inClose), 3)
+	for i := 1; i < len(inClose); i++ {
+		h, l, c := inHigh[i-1], inLow[i-1], inClose[i-1]
+		pivot := (h + l + c) / 3
+		p.Pivot[i] = pivot
+		p.Resistance[0][i] = 2*pivot - l
+		p.Support[0][i] = 2*pivot - h
+		p.Resistance[1][i] = pivot + (h - l)
+		p.Support[1][i] = pivot - (h - l)
+		p.Resistance[2][i] = h + 2*(pivot-l)
+		p.Support[2][i] = l - 2*(h-pivot)
+	}
+	return p
+}
+
+// FibonacciPivots - Fibonacci pivot points, with three levels at 38.2%,
+// 61.8% and 100% of the range off the pivot
+func FibonacciPivots(inHigh []float64, inLow []float64, inClose []float64) *Pivots {
+	p := newPivots(len(inClose), 3)
+	for i := 1; i < len(inClose); i++ {
+		h, l, c := inHigh[i-1], inLow[i-1], inClose[i-1]
+		pivot := (h + l + c) / 3
+		p.Pivot[i] = pivot
+		for k, ratio := range []float64{0.382, 0.618, 1} {
+			p.Resistance[k][i] = pivot + ratio*(h-l)
+			p.Support[k][i] = pivot - ratio*(h-l)
+		}
+	}
+	return p
+}
+
+// CamarillaPivots - Camarilla pivot points, with four levels off the close
+// at 1.1/12, 1.1/6, 1.1/4 and 1.1/2 of the range
+func CamarillaPivots(inHigh []float64, inLow []float64, inClose []float64) *Pivots {
+	p := newPivots(len(inClose), 4)
+	for i := 1; i < len(inClose); i++ {
+		h, l, c := inHigh[i-1], inLow[i-1], inClose[i-1]
+		p.Pivot[i] = (h + l + c) / 3
+		for k, divisor := range []float64{12, 6, 4, 2} {
+			p.Resistance[k][i] = c + (h-l)*1.1/divisor
+			p.Support[k][i] = c - (h-l)*1.1/divisor
+		}
+	}
+	return p
+}
@@ -0,0 +1,124 @@
+package talib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPivots(t *testing.T) {
+	h, l, c := []float64{110, 0}, []float64{90, 0}, []float64{106, 0}
+	classic := ClassicPivots(h, l, c)
+	if classic.Pivot[0] != 0 || math.Abs(classic.Pivot[1]-102) > 1e-9 {
+		t.Errorf("classic pivot %v", classic.Pivot)
+	}
+	for k, want := range [][2]float64{{114, 94}, {122, 82}, {134, 74}} {
+		if math.Abs(classic.Resistance[k][1]-want[0]) > 1e-9 || math.Abs(classic.Support[k][1]-want[1]) > 1e-9 {
+			t.Errorf("classic R%d/S%d %v/%v, want %v", k+1, k+1, classic.Resistance[k][1], classic.Support[k][1], want)
+		}
+	}
+	fib := FibonacciPivots(h, l, c)
+	if math.Abs(fib.Resistance[1][1]-114.36) > 1e-9 || math.Abs(fib.Support[2][1]-82) > 1e-9 {
+		t.Errorf("fibonacci R2 %v S3 %v", fib.Resistance[1][1], fib.Support[2][1])
+	}
+	camarilla := CamarillaPivots(h, l, c)
+	if len(camarilla.Resistance) != 4 || math.Abs(camarilla.Resistance[3][1]-117) > 1e-9 || math.Abs(camarilla.Support[0][1]-104.1666666666) > 1e-9 {
+		t.Errorf("camarilla R4 %v S1 %v", camarilla.Resistance[3][1], camarilla.Support[0][1])
+	}
+}
+
+func TestChannels(t *testing.T) {
+	qd := testQuotes()
+	upper, middle, lower := Donchian(qd.Highs, qd.Lows, 20)
+	for i := 19; i < len(qd.Closes); i++ {
+		hi, lo := qd.Highs[i], qd.Lows[i]
+		for k := i - 19; k < i; k++ {
+			hi, lo = math.Max(hi, qd.Highs[k]), math.Min(lo, qd.Lows[k])
+		}
+		if upper[i] != hi || lower[i] != lo || middle[i] != (hi+lo)/2 {
+			t.Fatalf("donchian at %d: %v %v %v", i, upper[i], middle[i], lower[i])
+		}
+	}
+
+	conversion, base, spanA, spanB, lagging := Ichimoku(qd.Highs, qd.Lows, qd.Closes, 9, 26, 52, 26)
+	spanB0 := midRange(qd.Highs, qd.Lows, 52)
+	for i := 77; i < len(qd.Closes); i++ {
+		if spanA[i] != (conversion[i-26]+base[i-26])/2 || spanB[i] != spanB0[i-26] {
+			t.Fatalf("ichimoku spans at %d", i)
+		}
+	}
+	if spanA[50] != 0 || spanA[51] == 0 || spanB[76] != 0 || lagging[25] != 0 || lagging[26] != qd.Closes[0] {
+		t.Errorf("ichimoku displacement")
+	}
+	// no line sees a later session
+	n := 200
+	c, b, a, s, l := Ichimoku(qd.Highs[:n], qd.Lows[:n], qd.Closes[:n], 9, 26, 52, 26)
+	for i := 0; i < n; i++ {
+		if c[i] != conversion[i] || b[i] != base[i] || a[i] != spanA[i] || s[i] != spanB[i] || l[i] != lagging[i] {
+			t.Fatalf("ichimoku at %d changes with later sessions", i)
+		}
+	}
+}
+
+// bars of 2 between the high and the low, the close 1 above the low
+var (
+	highs  = []float64{11, 12, 13, 12, 14}
+	lows   = []float64{9, 10, 11, 10, 12}
+	closes = []float64{10, 11, 12, 11, 13}
+)
+
+func TestKeltner(t *testing.T) {
+	// ema of 3: 11, 11, 12; true ranges 2, 2, 2, 3 so atr of 2: 2, 2, 2.5
+	upper, middle, lower := Keltner(highs, lows, closes, 3, 2, 2)
+	want := [][3]float64{{0, 0, 0}, {0, 0, 0}, {15, 11, 7}, {15, 11, 7}, {17, 12, 7}}
+	for i, w := range want {
+		if math.Abs(upper[i]-w[0]) > 1e-9 || math.Abs(middle[i]-w[1]) > 1e-9 || math.Abs(lower[i]-w[2]) > 1e-9 {
+			t.Errorf("keltner at %d: %v %v %v, want %v", i, upper[i], middle[i], lower[i], w)
+		}
+	}
+}
+
+func TestChandelierExit(t *testing.T) {
+	// atr of 3: 2, 7/3; highest highs 13, 14; lowest lows 10, 10
+	long, short := ChandelierExit(highs, lows, closes, 3, 2)
+	want := [][2]float64{{0, 0}, {0, 0}, {0, 0}, {9, 14}, {14 - 14.0/3, 10 + 14.0/3}}
+	for i, w := range want {
+		if math.Abs(long[i]-w[0]) > 1e-9 || math.Abs(short[i]-w[1]) > 1e-9 {
+			t.Errorf("chandelier at %d: %v %v, want %v", i, long[i], short[i], w)
+		}
+	}
+}
+
+func TestSupertrend(t *testing.T) {
+	var h, l, c []float64
+	for i := 0; i < 60; i++ {
+		// down 30 sessions, then up
+		p := 100 - float64(i)
+		if i >= 30 {
+			p = 40 + 2*float64(i)
+		}
+		h, l, c = append(h, p+1), append(l, p-1), append(c, p)
+	}
+	line, direction := Supertrend(h, l, c, 10, 3)
+	if direction[9] != 0 || direction[10] != -1 || direction[29] != -1 || direction[59] != 1 {
+		t.Errorf("directions %v", direction)
+	}
+	for i := 10; i < 60; i++ {
+		if direction[i] > 0 && line[i] >= l[i] || direction[i] < 0 && line[i] <= h[i] {
+			t.Fatalf("supertrend at %d: %v on the wrong side of %v-%v", i, line[i], l[i], h[i])
+		}
+	}
+}
+
+func TestVwap(t *testing.T) {
+	qd := testQuotes()
+	n := len(qd.Closes)
+	rolling := Vwap(qd.Highs, qd.Lows, qd.Closes, qd.Volumes, n)
+	anchored := AnchoredVwap(qd.Highs, qd.Lows, qd.Closes, qd.Volumes, 0)
+	if math.Abs(rolling[n-1]-anchored[n-1]) > 1e-9*anchored[n-1] || rolling[n-2] != 0 {
+		t.Errorf("rolling %v, anchored %v", rolling[n-1], anchored[n-1])
+	}
+	later := AnchoredVwap(qd.Highs, qd.Lows, qd.Closes, qd.Volumes, 100)
+	if later[99] != 0 || later[100] != (qd.Highs[100]+qd.Lows[100]+qd.Closes[100])/3 {
+		t.Errorf("anchored at 100: %v %v", later[99], later[100])
+	}
+}
@@ -0,0 +1,1060 @@
+package talib
+
+import (
+	"fmt"
+	"math"
+)
+
+/* Streaming Indicators */
+
+// The streaming indicators take one bar at a time and return the value of
+// that bar in constant time. For each bar they return what the batch
+// function of the same name returns for it, 0 while warming up, so a
+// backtest can update them as bars arrive instead of recomputing the
+// whole history on every bar.
+
+// Stream is an indicator of a single series
+type Stream interface {
+	Next(v float64) float64
+}
+
+// ring holds the last values of a stream
+type ring struct {
+	values []float64
+	next   int
+}
+
+func newRing(n int) *ring {
+	return &ring{values: make([]float64, n)}
+}
+
+// push adds a value and returns the oldest value the ring holds, 0 while
+// it is filling up
+func (r *ring) push(v float64) float64 {
+	r.values[r.next] = v
+	r.next++
+	if r.next == len(r.values) {
+		r.next = 0
+	}
+	return r.values[r.next]
+}
+
+// extreme tracks the highest or lowest value of a moving window, keeping
+// the latest bar when several hold it
+type extreme struct {
+	highest bool
+	bars    []int
+	values  []float64
+}
+
+// push adds the value of bar i and drops the bars before first
+func (e *extreme) push(i int, v float64, first int) {
+	for n := len(e.values); n > 0 && (e.highest && e.values[n-1] <= v || !e.highest && e.values[n-1] >= v); n-- {
+		e.bars, e.values = e.bars[:n-1], e.values[:n-1]
+	}
+	e.bars, e.values = append(e.bars, i), append(e.values, v)
+	for e.bars[0] < first {
+		e.bars, e.values = e.bars[1:], e.values[1:]
+	}
+}
+
+// checkPeriods returns an error when a period of a stream is below 1
+func checkPeriods(name string, periods ...int) error {
+	for _, period := range periods {
+		if period < 1 {
+			return fmt.Errorf("talib: %s period %d, needs at least 1", name, period)
+		}
+	}
+	return nil
+}
+
+// trueRange is the true range of a bar given the previous close
+func trueRange(high float64, low float64, prevClose float64) float64 {
+	greatest := high - low
+	if v := math.Abs(prevClose - high); v > greatest {
+		greatest = v
+	}
+	if v := math.Abs(prevClose - low); v > greatest {
+		greatest = v
+	}
+	return greatest
+}
+
+// SmaStream - Simple Moving Average
+type SmaStream struct {
+	period int
+	window *ring
+	total  float64
+	n      int
+}
+
+// NewSmaStream returns a streaming Sma
+func NewSmaStream(period int) (*SmaStream, error) {
+	if err := checkPeriods("Sma", period); err != nil {
+		return nil, err
+	}
+	return &SmaStream{period: period, window: newRing(period)}, nil
+}
+
+// Next adds a value and returns the average
+func (s *SmaStream) Next(v float64) float64 {
+	s.n++
+	oldest := s.window.push(v)
+	s.total += v
+	if s.n < s.period {
+		return 0
+	}
+	avg := s.total / float64(s.period)
+	s.total -= oldest
+	return avg
+}
+
+// EmaStream - Exponential Moving Average
+type EmaStream struct {
+	period int
+	k      float64
+	total  float64
+	prevMA float64
+	n      int
+}
+
+// NewEmaStream returns a streaming Ema
+func NewEmaStream(period int) (*EmaStream, error) {
+	if err := checkPeriods("Ema", period); err != nil {
+		return nil, err
+	}
+	return newEmaStream(period, 2.0/float64(period+1)), nil
+}
+
+func newEmaStream(period int, k float64) *EmaStream {
+	return &EmaStream{period: period, k: k}
+}
+
+// Next adds a value and returns the average
+func (s *EmaStream) Next(v float64) float64 {
+	s.n++
+	switch {
+	case s.n < s.period:
+		s.total += v
+		return 0
+	case s.n == s.period:
+		s.total += v
+		s.prevMA = s.total / float64(s.period)
+	default:
+		s.prevMA = ((v - s.prevMA) * s.k) + s.prevMA
+	}
+	return s.prevMA
+}
+
+// WmaStream - Weighted Moving Average
+type WmaStream struct {
+	period    int
+	window    *ring
+	periodSum float64
+	periodSub float64
+	trailing  float64
+	n         int
+}
+
+// NewWmaStream returns a streaming Wma
+func NewWmaStream(period int) (*WmaStream, error) {
+	if err := checkPeriods("Wma", period); err != nil {
+		return nil, err
+	}
+	return &WmaStream{period: period, window: newRing(period)}, nil
+}
+
+// Next adds a value and returns the average
+func (s *WmaStream) Next(v float64) float64 {
+	if s.period == 1 {
+		return v
+	}
+	s.n++
+	oldest := s.window.push(v)
+	s.periodSub += v
+	if s.n < s.period {
+		s.periodSum += v * float64(s.n)
+		return 0
+	}
+	s.periodSub -= s.trailing
+	s.periodSum += v * float64(s.period)
+	s.trailing = oldest
+	avg := s.periodSum / float64((s.period*(s.period+1))>>1)
+	s.periodSum -= s.periodSub
+	return avg
+}
+
+// cascade is a chain of Ema of one period, each fed the values of the one
+// before once it has settled, as Dema, Tema and T3 chain them
+type cascade struct {
+	period int
+	emas   []*EmaStream
+	values []float64
+	n      int
+}
+
+func newCascade(period int, depth int) *cascade {
+	c := &cascade{period: period, values: make([]float64, depth)}
+	for k := 0; k < depth; k++ {
+		c.emas = append(c.emas, newEmaStream(period, 2.0/float64(period+1)))
+	}
+	return c
+}
+
+// next adds a value and returns true once the last average has settled
+func (c *cascade) next(v float64) bool {
+	i := c.n
+	c.n++
+	for k, ema := range c.emas {
+		if i < k*(c.period-1) {
+			return false
+		}
+		v = ema.Next(v)
+		c.values[k] = v
+	}
+	return i >= len(c.emas)*(c.period-1)
+}
+
+// DemaStream - Double Exponential Moving Average
+type DemaStream struct {
+	emas *cascade
+}
+
+// NewDemaStream returns a streaming Dema
+func NewDemaStream(period int) (*DemaStream, error) {
+	if err := checkPeriods("Dema", period); err != nil {
+		return nil, err
+	}
+	return &DemaStream{emas: newCascade(period, 2)}, nil
+}
+
+// Next adds a value and returns the average
+func (s *DemaStream) Next(v float64) float64 {
+	if !s.emas.next(v) {
+		return 0
+	}
+	return (2.0 * s.emas.values[0]) - s.emas.values[1]
+}
+
+// TemaStream - Triple Exponential Moving Average
+type TemaStream struct {
+	emas *cascade
+}
+
+// NewTemaStream returns a streaming Tema
+func NewTemaStream(period int) (*TemaStream, error) {
+	if err := checkPeriods("Tema", period); err != nil {
+		return nil, err
+	}
+	return &TemaStream{emas: newCascade(period, 3)}, nil
+}
+
+// Next adds a value and returns the average
+func (s *TemaStream) Next(v float64) float64 {
+	if !s.emas.next(v) {
+		return 0
+	}
+	e := s.emas.values
+	return e[2] + ((3.0 * e[0]) - (3.0 * e[1]))
+}
+
+// T3Stream - Triple Exponential Moving Average (T3)
+type T3Stream struct {
+	emas           *cascade
+	c1, c2, c3, c4 float64
+}
+
+// NewT3Stream returns a streaming T3
+func NewT3Stream(period int, vFactor float64) (*T3Stream, error) {
+	if err := checkPeriods("T3", period); err != nil {
+		return nil, err
+	}
+	tempReal := vFactor * vFactor
+	c1 := -(tempReal * vFactor)
+	return &T3Stream{
+		emas: newCascade(period, 6),
+		c1:   c1,
+		c2:   3.0 * (tempReal - c1),
+		c3:   -6.0*tempReal - 3.0*(vFactor-c1),
+		c4:   1.0 + 3.0*vFactor - c1 + 3.0*tempReal,
+	}, nil
+}
+
+// Next adds a value and returns the average
+func (s *T3Stream) Next(v float64) float64 {
+	if !s.emas.next(v) {
+		return 0
+	}
+	e := s.emas.values
+	return s.c1*e[5] + s.c2*e[4] + s.c3*e[3] + s.c4*e[2]
+}
+
+// TrimaStream - Triangular Moving Average, the Sma of an Sma
+type TrimaStream struct {
+	first  *SmaStream
+	second *SmaStream
+	n      int
+}
+
+// NewTrimaStream returns a streaming Trima
+func NewTrimaStream(period int) (*TrimaStream, error) {
+	if err := checkPeriods("Trima", period); err != nil {
+		return nil, err
+	}
+	// weights 1, 2 .. m+1 .. 2, 1 for an odd period of 2m+1, and
+	// 1, 2 .. m, m .. 2, 1 for an even one of 2m
+	half := period >> 1
+	first, second := half+1, half+1
+	if period%2 == 0 {
+		first = half
+	}
+	s := &TrimaStream{}
+	s.first, _ = NewSmaStream(first)
+	s.second, _ = NewSmaStream(second)
+	return s, nil
+}
+
+// Next adds a value and returns the average
+func (s *TrimaStream) Next(v float64) float64 {
+	i := s.n
+	s.n++
+	v = s.first.Next(v)
+	if i < s.first.period-1 {
+		return 0
+	}
+	return s.second.Next(v)
+}
+
+// KamaStream - Kaufman Adaptive Moving Average
+type KamaStream struct {
+	period   int
+	window   *ring // the last period+1 values
+	changes  *ring // their changes from the value before
+	sumROC1  float64
+	prev     float64
+	prevKAMA float64
+	n        int
+}
+
+// NewKamaStream returns a streaming Kama
+func NewKamaStream(period int) (*KamaStream, error) {
+	if err := checkPeriods("Kama", period); err != nil {
+		return nil, err
+	}
+	return &KamaStream{period: period, window: newRing(period + 1), changes: newRing(period + 1)}, nil
+}
+
+// Next adds a value and returns the average
+func (s *KamaStream) Next(v float64) float64 {
+	const constMax = 2.0 / (30.0 + 1.0)
+	const constDiff = 2.0/(2.0+1.0) - constMax
+	i := s.n
+	s.n++
+	trailing := s.window.push(v)
+	change := 0.0
+	if i > 0 {
+		change = math.Abs(v - s.prev)
+	}
+	prev := s.prev
+	s.prev = v
+	trailingChange := s.changes.push(change)
+	switch {
+	case i < s.period:
+		s.sumROC1 += change
+		return 0
+	case i == s.period:
+		s.sumROC1 += change
+		s.prevKAMA = prev
+	default:
+		s.sumROC1 -= trailingChange
+		s.sumROC1 += change
+	}
+	periodROC := v - trailing
+	tempReal := 1.0
+	if !((s.sumROC1 <= periodROC) || (((-(0.00000000000001)) < s.sumROC1) && (s.sumROC1 < (0.00000000000001)))) {
+		tempReal = math.Abs(periodROC / s.sumROC1)
+	}
+	tempReal = (tempReal * constDiff) + constMax
+	tempReal *= tempReal
+	s.prevKAMA = ((v - s.prevKAMA) * tempReal) + s.prevKAMA
+	return s.prevKAMA
+}
+
+// hilbert is a stage of the Hilbert transform of Mama, which keeps apart
+// the values of odd and even bars
+type hilbert struct {
+	odd, even                   [3]float64
+	prevOdd, prevEven           float64
+	prevInputOdd, prevInputEven float64
+}
+
+// transform adds the value of a bar and returns the stage's output
+func (h *hilbert) transform(v float64, even bool, idx int, adjustedPrevPeriod float64) float64 {
+	const a, b = 0.0962, 0.5769
+	hilbertTempReal := a * v
+	var out float64
+	if even {
+		out = -h.even[idx]
+		h.even[idx] = hilbertTempReal
+		out += hilbertTempReal
+		out -= h.prevEven
+		h.prevEven = b * h.prevInputEven
+		out += h.prevEven
+		h.prevInputEven = v
+	} else {
+		out = -h.odd[idx]
+		h.odd[idx] = hilbertTempReal
+		out += hilbertTempReal
+		out -= h.prevOdd
+		h.prevOdd = b * h.prevInputOdd
+		out += h.prevOdd
+		h.prevInputOdd = v
+	}
+	return out * adjustedPrevPeriod
+}
+
+// MamaStream - MESA Adaptive Moving Average
+type MamaStream struct {
+	fastLimit        float64
+	slowLimit        float64
+	window           *ring // the last 4 values, smoothed by a Wma
+	periodWMASub     float64
+	periodWMASum     float64
+	trailingWMAValue float64
+	hilbertIdx       int
+	detrender        hilbert
+	q1               hilbert
+	jI               hilbert
+	jQ               hilbert
+	i1ForOddPrev3    float64
+	i1ForOddPrev2    float64
+	i1ForEvenPrev3   float64
+	i1ForEvenPrev2   float64
+	previ2, prevq2   float64
+	re, im           float64
+	prevPhase        float64
+	period           float64
+	mama, fama       float64
+	n                int
+}
+
+// NewMamaStream returns a streaming Mama
+func NewMamaStream(fastLimit float64, slowLimit float64) (*MamaStream, error) {
+	if !(fastLimit > 0 && fastLimit <= 1 && slowLimit > 0 && slowLimit <= 1) {
+		return nil, fmt.Errorf("talib: Mama limits %v and %v, need to be above 0 and at most 1", fastLimit, slowLimit)
+	}
+	return &MamaStream{fastLimit: fastLimit, slowLimit: slowLimit, window: newRing(4)}, nil
+}
+
+// Next adds a value and returns mama and fama
+func (s *MamaStream) Next(v float64) (float64, float64) {
+	const lookback = 32
+	rad2Deg := 180.0 / (4.0 * math.Atan(1))
+	i := s.n
+	s.n++
+	trailing := s.window.push(v)
+	if i < 3 {
+		s.periodWMASub += v
+		s.periodWMASum += v * float64(i+1)
+		return 0, 0
+	}
+	s.periodWMASub += v
+	s.periodWMASub -= s.trailingWMAValue
+	s.periodWMASum += v * 4.0
+	s.trailingWMAValue = trailing
+	smoothedValue := s.periodWMASum * 0.1
+	s.periodWMASum -= s.periodWMASub
+	if i < 12 {
+		return 0, 0
+	}
+
+	adjustedPrevPeriod := (0.075 * s.period) + 0.54
+	even := i%2 == 0
+	detrender := s.detrender.transform(smoothedValue, even, s.hilbertIdx, adjustedPrevPeriod)
+	q1 := s.q1.transform(detrender, even, s.hilbertIdx, adjustedPrevPeriod)
+	var q2, i2, tempReal2 float64
+	if even {
+		jI := s.jI.transform(s.i1ForEvenPrev3, even, s.hilbertIdx, adjustedPrevPeriod)
+		jQ := s.jQ.transform(q1, even, s.hilbertIdx, adjustedPrevPeriod)
+		s.hilbertIdx++
+		if s.hilbertIdx == 3 {
+			s.hilbertIdx = 0
+		}
+		q2 = (0.2 * (q1 + jI)) + (0.8 * s.prevq2)
+		i2 = (0.2 * (s.i1ForEvenPrev3 - jQ)) + (0.8 * s.previ2)
+		s.i1ForOddPrev3 = s.i1ForOddPrev2
+		s.i1ForOddPrev2 = detrender
+		if s.i1ForEvenPrev3 != 0.0 {
+			tempReal2 = (math.Atan(q1/s.i1ForEvenPrev3) * rad2Deg)
+		}
+	} else {
+		jI := s.jI.transform(s.i1ForOddPrev3, even, s.hilbertIdx, adjustedPrevPeriod)
+		jQ := s.jQ.transform(q1, even, s.hilbertIdx, adjustedPrevPeriod)
+		q2 = (0.2 * (q1 + jI)) + (0.8 * s.prevq2)
+		i2 = (0.2 * (s.i1ForOddPrev3 - jQ)) + (0.8 * s.previ2)
+		s.i1ForEvenPrev3 = s.i1ForEvenPrev2
+		s.i1ForEvenPrev2 = detrender
+		if s.i1ForOddPrev3 != 0.0 {
+			tempReal2 = (math.Atan(q1/s.i1ForOddPrev3) * rad2Deg)
+		}
+	}
+	tempReal := s.prevPhase - tempReal2
+	s.prevPhase = tempReal2
+	if tempReal < 1.0 {
+		tempReal = 1.0
+	}
+	if tempReal > 1.0 {
+		tempReal = s.fastLimit / tempReal
+		if tempReal < s.slowLimit {
+			tempReal = s.slowLimit
+		}
+	} else {
+		tempReal = s.fastLimit
+	}
+	s.mama = (tempReal * v) + ((1 - tempReal) * s.mama)
+	tempReal *= 0.5
+	s.fama = (tempReal * s.mama) + ((1 - tempReal) * s.fama)
+	mama, fama := 0.0, 0.0
+	if i >= lookback {
+		mama, fama = s.mama, s.fama
+	}
+
+	s.re = (0.2 * ((i2 * s.previ2) + (q2 * s.prevq2))) + (0.8 * s.re)
+	s.im = (0.2 * ((i2 * s.prevq2) - (q2 * s.previ2))) + (0.8 * s.im)
+	s.prevq2 = q2
+	s.previ2 = i2
+	tempReal = s.period
+	if (s.im != 0.0) && (s.re != 0.0) {
+		s.period = 360.0 / (math.Atan(s.im/s.re) * rad2Deg)
+	}
+	tempReal2 = 1.5 * tempReal
+	if s.period > tempReal2 {
+		s.period = tempReal2
+	}
+	tempReal2 = 0.67 * tempReal
+	if s.period < tempReal2 {
+		s.period = tempReal2
+	}
+	if s.period < 6 {
+		s.period = 6
+	} else if s.period > 50 {
+		s.period = 50
+	}
+	s.period = (0.2 * s.period) + (0.8 * tempReal)
+	return mama, fama
+}
+
+// mamaStream is the mama of a Mama, as Ma takes it
+type mamaStream struct {
+	*MamaStream
+}
+
+func (s mamaStream) Next(v float64) float64 {
+	mama, _ := s.MamaStream.Next(v)
+	return mama
+}
+
+type identity struct{}
+
+func (identity) Next(v float64) float64 {
+	return v
+}
+
+// NewMaStream returns a streaming Ma of any of the types Ma takes
+func NewMaStream(period int, maType MaType) (Stream, error) {
+	if err := checkPeriods("Ma", period); err != nil {
+		return nil, err
+	}
+	if period == 1 {
+		return identity{}, nil
+	}
+	switch maType {
+	case SMA:
+		return NewSmaStream(period)
+	case EMA:
+		return NewEmaStream(period)
+	case WMA:
+		return NewWmaStream(period)
+	case DEMA:
+		return NewDemaStream(period)
+	case TEMA:
+		return NewTemaStream(period)
+	case TRIMA:
+		return NewTrimaStream(period)
+	case KAMA:
+		return NewKamaStream(period)
+	case MAMA:
+		mama, err := NewMamaStream(0.5, 0.05)
+		return mamaStream{mama}, err
+	case T3MA:
+		return NewT3Stream(period, 0.7)
+	}
+	return nil, fmt.Errorf("talib: unknown moving average type %d", maType)
+}
+
+// VarStream - Variance
+type VarStream struct {
+	period int
+	window *ring
+	total1 float64
+	total2 float64
+	n      int
+}
+
+// NewVarStream returns a streaming Var
+func NewVarStream(period int) (*VarStream, error) {
+	if err := checkPeriods("Var", period); err != nil {
+		return nil, err
+	}
+	return &VarStream{period: period, window: newRing(period)}, nil
+}
+
+// Next adds a value and returns the variance
+func (s *VarStream) Next(v float64) float64 {
+	s.n++
+	oldest := s.window.push(v)
+	s.total1 += v
+	s.total2 += v * v
+	if s.n < s.period {
+		return 0
+	}
+	mean1 := s.total1 / float64(s.period)
+	mean2 := s.total2 / float64(s.period)
+	s.total1 -= oldest
+	s.total2 -= oldest * oldest
+	return mean2 - mean1*mean1
+}
+
+// BBandsStream - Bollinger Bands
+type BBandsStream struct {
+	ma        Stream
+	variance  *VarStream
+	nbDevUp   float64
+	nbDevDown float64
+}
+
+// NewBBandsStream returns streaming BBands
+func NewBBandsStream(period int, nbDevUp float64, nbDevDown float64, maType MaType) (*BBandsStream, error) {
+	ma, err := NewMaStream(period, maType)
+	if err != nil {
+		return nil, err
+	}
+	variance, err := NewVarStream(period)
+	if err != nil {
+		return nil, err
+	}
+	return &BBandsStream{ma: ma, variance: variance, nbDevUp: nbDevUp, nbDevDown: nbDevDown}, nil
+}
+
+// Next adds a value and returns the upper, middle and lower band
+func (s *BBandsStream) Next(v float64) (float64, float64, float64) {
+	middle := s.ma.Next(v)
+	stdDev := 0.0
+	if variance := s.variance.Next(v); !(variance < 0.00000000000001) {
+		stdDev = math.Sqrt(variance)
+	}
+	return middle + stdDev*s.nbDevUp, middle, middle - stdDev*s.nbDevDown
+}
+
+// MacdStream - Moving Average Convergence/Divergence
+type MacdStream struct {
+	fast     *EmaStream
+	slow     *EmaStream
+	signal   *EmaStream
+	lookback int
+	n        int
+}
+
+// NewMacdStream returns a streaming Macd. A fast or slow period of 0 is
+// that of the classic Macd, 12 or 26.
+func NewMacdStream(fastPeriod int, slowPeriod int, signalPeriod int) (*MacdStream, error) {
+	if fastPeriod < 0 || slowPeriod < 0 {
+		return nil, fmt.Errorf("talib: Macd periods %d and %d, need to be at least 0", fastPeriod, slowPeriod)
+	}
+	if err := checkPeriods("Macd signal", signalPeriod); err != nil {
+		return nil, err
+	}
+	if slowPeriod < fastPeriod {
+		slowPeriod, fastPeriod = fastPeriod, slowPeriod
+	}
+	k1, k2 := 0.075, 0.15
+	if slowPeriod != 0 {
+		k1 = 2.0 / float64(slowPeriod+1)
+	} else {
+		slowPeriod = 26
+	}
+	if fastPeriod != 0 {
+		k2 = 2.0 / float64(fastPeriod+1)
+	} else {
+		fastPeriod = 12
+	}
+	return &MacdStream{
+		fast:     newEmaStream(fastPeriod, k2),
+		slow:     newEmaStream(slowPeriod, k1),
+		signal:   newEmaStream(signalPeriod, 2.0/float64(signalPeriod+1)),
+		lookback: (signalPeriod - 1) + (slowPeriod - 1),
+	}, nil
+}
+
+// Next adds a value and returns the macd, its signal and their difference
+func (s *MacdStream) Next(v float64) (float64, float64, float64) {
+	i := s.n
+	s.n++
+	fast, slow := s.fast.Next(v), s.slow.Next(v)
+	macd := 0.0
+	if i >= s.lookback-1 {
+		macd = fast - slow
+	}
+	signal := s.signal.Next(macd)
+	if i < s.lookback {
+		return macd, signal, 0
+	}
+	return macd, signal, macd - signal
+}
+
+// AtrStream - Average True Range
+type AtrStream struct {
+	period    int
+	prevClose float64
+	total     float64
+	prevATR   float64
+	n         int
+}
+
+// NewAtrStream returns a streaming Atr
+func NewAtrStream(period int) (*AtrStream, error) {
+	if err := checkPeriods("Atr", period); err != nil {
+		return nil, err
+	}
+	return &AtrStream{period: period}, nil
+}
+
+// Next adds a bar and returns the average true range
+func (s *AtrStream) Next(high float64, low float64, close float64) float64 {
+	i := s.n
+	s.n++
+	tr := 0.0
+	if i > 0 {
+		tr = trueRange(high, low, s.prevClose)
+	}
+	s.prevClose = close
+	switch {
+	case s.period < 1:
+		return 0
+	case s.period == 1:
+		return tr
+	case i == 0:
+		return 0
+	case i < s.period:
+		s.total += tr
+		return 0
+	case i == s.period:
+		s.total += tr
+		s.prevATR = s.total / float64(s.period)
+	default:
+		s.prevATR *= float64(s.period) - 1.0
+		s.prevATR += tr
+		s.prevATR /= float64(s.period)
+	}
+	return s.prevATR
+}
+
+// RsiStream - Relative strength index
+type RsiStream struct {
+	period    int
+	prevValue float64
+	prevGain  float64
+	prevLoss  float64
+	n         int
+}
+
+// NewRsiStream returns a streaming Rsi
+func NewRsiStream(period int) (*RsiStream, error) {
+	if err := checkPeriods("Rsi", period); err != nil {
+		return nil, err
+	}
+	return &RsiStream{period: period}, nil
+}
+
+// Next adds a value and returns the index
+func (s *RsiStream) Next(v float64) float64 {
+	i := s.n
+	s.n++
+	if s.period < 2 {
+		return 0
+	}
+	if i == 0 {
+		s.prevValue = v
+		return 0
+	}
+	diff := v - s.prevValue
+	s.prevValue = v
+	period := float64(s.period)
+	if i > s.period {
+		s.prevLoss *= period - 1
+		s.prevGain *= period - 1
+	}
+	if diff < 0 {
+		s.prevLoss -= diff
+	} else {
+		s.prevGain += diff
+	}
+	if i < s.period {
+		return 0
+	}
+	s.prevLoss /= period
+	s.prevGain /= period
+	if total := s.prevGain + s.prevLoss; !((-0.00000000000001 < total) && (total < 0.00000000000001)) {
+		return 100.0 * (s.prevGain / total)
+	}
+	return 0
+}
+
+// MfiStream - Money Flow Index
+type MfiStream struct {
+	period    int
+	flows     []moneyFlow
+	next      int
+	prevValue float64
+	posSumMF  float64
+	negSumMF  float64
+	n         int
+}
+
+// NewMfiStream returns a streaming Mfi
+func NewMfiStream(period int) (*MfiStream, error) {
+	if err := checkPeriods("Mfi", period); err != nil {
+		return nil, err
+	}
+	return &MfiStream{period: period, flows: make([]moneyFlow, period)}, nil
+}
+
+// Next adds a bar and returns the index
+func (s *MfiStream) Next(high float64, low float64, close float64, volume float64) float64 {
+	i := s.n
+	s.n++
+	typical := (high + low + close) / 3.0
+	if i == 0 {
+		s.prevValue = typical
+		return 0
+	}
+	flow := &s.flows[s.next]
+	if i > s.period {
+		s.posSumMF -= flow.positive
+		s.negSumMF -= flow.negative
+	}
+	diff := typical - s.prevValue
+	s.prevValue = typical
+	typical *= volume
+	switch {
+	case diff < 0:
+		flow.negative = typical
+		s.negSumMF += typical
+		flow.positive = 0.0
+	case diff > 0:
+		flow.positive = typical
+		s.posSumMF += typical
+		flow.negative = 0.0
+	default:
+		flow.positive = 0.0
+		flow.negative = 0.0
+	}
+	s.next++
+	if s.next == s.period {
+		s.next = 0
+	}
+	if i < s.period {
+		return 0
+	}
+	if total := s.posSumMF + s.negSumMF; !(total < 1.0) {
+		return 100.0 * (s.posSumMF / total)
+	}
+	return 0
+}
+
+// AroonStream - Aroon
+type AroonStream struct {
+	period  int
+	highest extreme
+	lowest  extreme
+	n       int
+}
+
+// NewAroonStream returns a streaming Aroon
+func NewAroonStream(period int) (*AroonStream, error) {
+	if err := checkPeriods("Aroon", period); err != nil {
+		return nil, err
+	}
+	return &AroonStream{period: period, highest: extreme{highest: true}}, nil
+}
+
+// Next adds a bar and returns aroondown and aroonup, in the order of Aroon
+func (s *AroonStream) Next(high float64, low float64) (float64, float64) {
+	i := s.n
+	s.n++
+	s.highest.push(i, high, i-s.period)
+	s.lowest.push(i, low, i-s.period)
+	if i < s.period {
+		return 0, 0
+	}
+	factor := 100.0 / float64(s.period)
+	return factor * float64(s.period-(i-s.lowest.bars[0])), factor * float64(s.period-(i-s.highest.bars[0]))
+}
+
+// AdxStream - Average Directional Movement Index
+type AdxStream struct {
+	period    int
+	prevHigh  float64
+	prevLow   float64
+	prevClose float64
+	plusDM    float64
+	minusDM   float64
+	tr        float64
+	sumDX     float64
+	prevADX   float64
+	n         int
+}
+
+// NewAdxStream returns a streaming Adx
+func NewAdxStream(period int) (*AdxStream, error) {
+	if err := checkPeriods("Adx", period); err != nil {
+		return nil, err
+	}
+	return &AdxStream{period: period}, nil
+}
+
+// Next adds a bar and returns the index
+func (s *AdxStream) Next(high float64, low float64, close float64) float64 {
+	i := s.n
+	s.n++
+	if i == 0 {
+		s.prevHigh, s.prevLow, s.prevClose = high, low, close
+		return 0
+	}
+	period := float64(s.period)
+	diffP := high - s.prevHigh
+	s.prevHigh = high
+	diffM := s.prevLow - low
+	s.prevLow = low
+	if i >= s.period {
+		s.minusDM -= s.minusDM / period
+		s.plusDM -= s.plusDM / period
+	}
+	if (diffM > 0) && (diffP < diffM) {
+		s.minusDM += diffM
+	} else if (diffP > 0) && (diffP > diffM) {
+		s.plusDM += diffP
+	}
+	tr := trueRange(high, low, s.prevClose)
+	s.prevClose = close
+	if i < s.period {
+		s.tr += tr
+		return 0
+	}
+	s.tr = s.tr - (s.tr / period) + tr
+	dx, ok := 0.0, false
+	if !(((-(0.00000000000001)) < s.tr) && (s.tr < (0.00000000000001))) {
+		minusDI := (100.0 * (s.minusDM / s.tr))
+		plusDI := (100.0 * (s.plusDM / s.tr))
+		if total := minusDI + plusDI; !(((-(0.00000000000001)) < total) && (total < (0.00000000000001))) {
+			dx, ok = (100.0 * (math.Abs(minusDI-plusDI) / total)), true
+		}
+	}
+	switch {
+	case i < 2*s.period-1:
+		if ok {
+			s.sumDX += dx
+		}
+		return 0
+	case i == 2*s.period-1:
+		if ok {
+			s.sumDX += dx
+		}
+		s.prevADX = s.sumDX / period
+	case ok:
+		s.prevADX = (((s.prevADX * (period - 1)) + dx) / period)
+	}
+	return s.prevADX
+}
+
+// StochStream - Stochastic
+type StochStream struct {
+	lookbackK int
+	lookback  int
+	highest   extreme
+	lowest    extreme
+	slowK     Stream
+	slowD     Stream
+	n         int
+}
+
+// NewStochStream returns a streaming Stoch
+func NewStochStream(fastKPeriod int, slowKPeriod int, slowKMAType MaType, slowDPeriod int, slowDMAType MaType) (*StochStream, error) {
+	if err := checkPeriods("Stoch fastk", fastKPeriod); err != nil {
+		return nil, err
+	}
+	slowK, err := NewMaStream(slowKPeriod, slowKMAType)
+	if err != nil {
+		return nil, err
+	}
+	slowD, err := NewMaStream(slowDPeriod, slowDMAType)
+	if err != nil {
+		return nil, err
+	}
+	return &StochStream{
+		lookbackK: fastKPeriod - 1,
+		lookback:  (fastKPeriod - 1) + (slowKPeriod - 1) + (slowDPeriod - 1),
+		highest:   extreme{highest: true},
+		slowK:     slowK,
+		slowD:     slowD,
+	}, nil
+}
+
+// Next adds a bar and returns slowk and slowd
+func (s *StochStream) Next(high float64, low float64, close float64) (float64, float64) {
+	i := s.n
+	s.n++
+	s.highest.push(i, high, i-s.lookbackK)
+	s.lowest.push(i, low, i-s.lookbackK)
+	if i < s.lookbackK {
+		return 0, 0
+	}
+	highest, lowest := s.highest.values[0], s.lowest.values[0]
+	fastK := 0.0
+	if diff := (highest - lowest) / 100.0; diff != 0.0 {
+		fastK = (close - lowest) / diff
+	}
+	slowK := s.slowK.Next(fastK)
+	slowD := s.slowD.Next(slowK)
+	if i < s.lookback {
+		return 0, 0
+	}
+	return slowK, slowD
+}
+
+// ObvStream - On Balance Volume
+type ObvStream struct {
+	prevOBV  float64
+	prevReal float64
+	n        int
+}
+
+// NewObvStream returns a streaming Obv
+func NewObvStream() *ObvStream {
+	return &ObvStream{}
+}
+
+// Next adds a bar and returns the running volume
+func (s *ObvStream) Next(v float64, volume float64) float64 {
+	if s.n == 0 {
+		s.prevOBV, s.prevReal = volume, v
+	}
+	s.n++
+	if v > s.prevReal {
+		s.prevOBV += volume
+	} else if v < s.prevReal {
+		s.prevOBV -= volume
+	}
+	s.prevReal = v
+	return s.prevOBV
+}
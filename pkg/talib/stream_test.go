@@ -0,0 +1,244 @@
+package talib
+
+import (
+	"fmt"
+	"math"
+	"pkg/quotes"
+	"testing"
+	"time"
+)
+
+func testQuotes() *quotes.QuoteData {
+	g := &quotes.Generator{Symbol: "SYNTH", Start: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Seed: 3, Price: 100, Volume: 1e5}
+	return g.Generate(quotes.JumpDiffusion{GBM: quotes.GBM{Drift: 0.05, Volatility: 0.3}, JumpsPerYear: 4, JumpStd: 0.08}, 400)
+}
+
+// same fails the test when a streamed series differs from the batch one
+func same(t *testing.T, name string, batch []float64, streamed []float64) {
+	t.Helper()
+	for i := range batch {
+		if math.Abs(batch[i]-streamed[i]) > 1e-9*math.Max(1, math.Abs(batch[i])) {
+			t.Fatalf("%s: bar %d: batch %v, streamed %v", name, i, batch[i], streamed[i])
+		}
+	}
+}
+
+// ok fails the test at once on the error of a constructor
+func ok(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// maTypes are the moving averages Ma takes
+var maTypes = []MaType{SMA, EMA, WMA, DEMA, TEMA, TRIMA, KAMA, MAMA, T3MA}
+
+func TestMovingAverageStreams(t *testing.T) {
+	qd := testQuotes()
+	for _, period := range []int{1, 2, 5, 20, 50} {
+		sma, err := NewSmaStream(period)
+		ok(t, err)
+		ema, err := NewEmaStream(period)
+		ok(t, err)
+		wma, err := NewWmaStream(period)
+		ok(t, err)
+		variance, err := NewVarStream(period)
+		ok(t, err)
+		n := len(qd.Closes)
+		s, e, w, v := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+		for i, c := range qd.Closes {
+			s[i], e[i], w[i], v[i] = sma.Next(c), ema.Next(c), wma.Next(c), variance.Next(c)
+		}
+		same(t, "Sma", Sma(qd.Closes, period), s)
+		same(t, "Ema", Ema(qd.Closes, period), e)
+		same(t, "Wma", Wma(qd.Closes, period), w)
+		same(t, "Var", Var(qd.Closes, period), v)
+	}
+}
+
+func TestMaStreams(t *testing.T) {
+	qd := testQuotes()
+	n := len(qd.Closes)
+	for _, maType := range maTypes {
+		for _, period := range []int{1, 2, 3, 4, 5, 20, 50} {
+			ma, err := NewMaStream(period, maType)
+			ok(t, err)
+			m := make([]float64, n)
+			for i, c := range qd.Closes {
+				m[i] = ma.Next(c)
+			}
+			same(t, fmt.Sprintf("Ma %d of type %d", period, maType), Ma(qd.Closes, period, maType), m)
+		}
+	}
+	for _, vFactor := range []float64{0, 0.7, 1} {
+		t3, err := NewT3Stream(5, vFactor)
+		ok(t, err)
+		m := make([]float64, n)
+		for i, c := range qd.Closes {
+			m[i] = t3.Next(c)
+		}
+		same(t, fmt.Sprintf("T3 of %v", vFactor), T3(qd.Closes, 5, vFactor), m)
+	}
+	for _, limits := range [][2]float64{{0.5, 0.05}, {0.9, 0.2}} {
+		mama, err := NewMamaStream(limits[0], limits[1])
+		ok(t, err)
+		m, f := make([]float64, n), make([]float64, n)
+		for i, c := range qd.Closes {
+			m[i], f[i] = mama.Next(c)
+		}
+		bm, bf := Mama(qd.Closes, limits[0], limits[1])
+		same(t, "Mama", bm, m)
+		same(t, "Mama fama", bf, f)
+	}
+}
+
+func TestStreamPeriods(t *testing.T) {
+	for name, err := range map[string]error{
+		"Sma":    second(NewSmaStream(0)),
+		"Ema":    second(NewEmaStream(-1)),
+		"Wma":    second(NewWmaStream(0)),
+		"Var":    second(NewVarStream(0)),
+		"Dema":   second(NewDemaStream(0)),
+		"Tema":   second(NewTemaStream(0)),
+		"T3":     second(NewT3Stream(0, 0.7)),
+		"Trima":  second(NewTrimaStream(0)),
+		"Kama":   second(NewKamaStream(0)),
+		"Mama":   second(NewMamaStream(0, 0.05)),
+		"Ma":     second(NewMaStream(0, SMA)),
+		"Ma 10":  second(NewMaStream(5, MaType(10))),
+		"BBands": second(NewBBandsStream(0, 2, 2, SMA)),
+		"Macd":   second(NewMacdStream(12, 26, 0)),
+		"Atr":    second(NewAtrStream(0)),
+		"Rsi":    second(NewRsiStream(0)),
+		"Mfi":    second(NewMfiStream(0)),
+		"Aroon":  second(NewAroonStream(0)),
+		"Adx":    second(NewAdxStream(0)),
+		"Stoch":  second(NewStochStream(5, 0, SMA, 3, SMA)),
+	} {
+		if err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+// second returns the error of a constructor
+func second(_ interface{}, err error) error {
+	return err
+}
+
+func TestBBandsStream(t *testing.T) {
+	qd := testQuotes()
+	for _, maType := range maTypes {
+		for _, devs := range [][2]float64{{2, 2}, {1, 1}, {1, 2.5}, {2, 1}} {
+			bb, err := NewBBandsStream(20, devs[0], devs[1], maType)
+			ok(t, err)
+			n := len(qd.Closes)
+			upper, middle, lower := make([]float64, n), make([]float64, n), make([]float64, n)
+			for i, c := range qd.Closes {
+				upper[i], middle[i], lower[i] = bb.Next(c)
+			}
+			u, m, l := BBands(qd.Closes, 20, devs[0], devs[1], maType)
+			same(t, "BBands upper", u, upper)
+			same(t, "BBands middle", m, middle)
+			same(t, "BBands lower", l, lower)
+		}
+	}
+}
+
+func TestMacdStream(t *testing.T) {
+	qd := testQuotes()
+	for _, p := range [][3]int{{12, 26, 9}, {26, 12, 9}, {0, 0, 9}, {5, 35, 5}} {
+		macd, err := NewMacdStream(p[0], p[1], p[2])
+		ok(t, err)
+		n := len(qd.Closes)
+		m, s, h := make([]float64, n), make([]float64, n), make([]float64, n)
+		for i, c := range qd.Closes {
+			m[i], s[i], h[i] = macd.Next(c)
+		}
+		bm, bs, bh := Macd(qd.Closes, p[0], p[1], p[2])
+		same(t, "Macd", bm, m)
+		same(t, "Macd signal", bs, s)
+		same(t, "Macd hist", bh, h)
+	}
+}
+
+func TestBarStreams(t *testing.T) {
+	qd := testQuotes()
+	n := len(qd.Closes)
+	for _, period := range []int{1, 2, 14, 20} {
+		atr, err := NewAtrStream(period)
+		ok(t, err)
+		rsi, err := NewRsiStream(period)
+		ok(t, err)
+		mfi, err := NewMfiStream(period)
+		ok(t, err)
+		aroon, err := NewAroonStream(period)
+		ok(t, err)
+		adx, err := NewAdxStream(period)
+		ok(t, err)
+		a, r, m, dn, up, x := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+		for i := range qd.Closes {
+			h, l, c, v := qd.Highs[i], qd.Lows[i], qd.Closes[i], qd.Volumes[i]
+			a[i], r[i], m[i], x[i] = atr.Next(h, l, c), rsi.Next(c), mfi.Next(h, l, c, v), adx.Next(h, l, c)
+			dn[i], up[i] = aroon.Next(h, l)
+		}
+		same(t, "Atr", Atr(qd.Highs, qd.Lows, qd.Closes, period), a)
+		same(t, "Rsi", Rsi(qd.Closes, period), r)
+		same(t, "Mfi", Mfi(qd.Highs, qd.Lows, qd.Closes, qd.Volumes, period), m)
+		same(t, "Adx", Adx(qd.Highs, qd.Lows, qd.Closes, period), x)
+		bdn, bup := Aroon(qd.Highs, qd.Lows, period)
+		same(t, "Aroon down", bdn, dn)
+		same(t, "Aroon up", bup, up)
+	}
+
+	obv := NewObvStream()
+	o := make([]float64, n)
+	for i := range qd.Closes {
+		o[i] = obv.Next(qd.Closes[i], qd.Volumes[i])
+	}
+	same(t, "Obv", Obv(qd.Closes, qd.Volumes), o)
+
+	for _, p := range [][3]int{{5, 3, 3}, {14, 3, 5}, {14, 1, 1}} {
+		for _, maType := range maTypes {
+			stoch, err := NewStochStream(p[0], p[1], maType, p[2], maType)
+			ok(t, err)
+			k, d := make([]float64, n), make([]float64, n)
+			for i := range qd.Closes {
+				k[i], d[i] = stoch.Next(qd.Highs[i], qd.Lows[i], qd.Closes[i])
+			}
+			bk, bd := Stoch(qd.Highs, qd.Lows, qd.Closes, p[0], p[1], maType, p[2], maType)
+			same(t, "Stoch k", bk, k)
+			same(t, "Stoch d", bd, d)
+		}
+	}
+}
+
+// Aroon and Stoch look for the latest extreme of their window, which flat
+// stretches put to the test
+func TestStreamsOnFlatBars(t *testing.T) {
+	highs := []float64{10, 10, 10, 11, 11, 10, 10, 10, 10, 12, 12, 9, 9, 9, 9, 9, 10, 10}
+	lows := []float64{9, 9, 8, 8, 9, 9, 9, 7, 7, 7, 8, 8, 8, 8, 8, 8, 9, 9}
+	closes := make([]float64, len(highs))
+	for i := range highs {
+		closes[i] = (highs[i] + lows[i]) / 2
+	}
+	for _, period := range []int{2, 3, 5} {
+		aroon, err := NewAroonStream(period)
+		ok(t, err)
+		stoch, err := NewStochStream(period, 2, SMA, 2, SMA)
+		ok(t, err)
+		n := len(highs)
+		dn, up, k, d := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+		for i := range highs {
+			dn[i], up[i] = aroon.Next(highs[i], lows[i])
+			k[i], d[i] = stoch.Next(highs[i], lows[i], closes[i])
+		}
+		bdn, bup := Aroon(highs, lows, period)
+		same(t, "Aroon down", bdn, dn)
+		same(t, "Aroon up", bup, up)
+		bk, bd := Stoch(highs, lows, closes, period, 2, SMA, 2, SMA)
+		same(t, "Stoch k", bk, k)
+		same(t, "Stoch d", bd, d)
+	}
+}
@@ -3971,7 +3971,7 @@ func HtDcPhase(inReal []float64) []float64 {
 	prevJQInputOdd := 0.0
 	prevJQInputEven := 0.0
 	period := 0.0
-	outIdx := 0
+	outIdx := 63
 	previ2 := 0.0
 	prevq2 := 0.0
 	Re := 0.0
@@ -0,0 +1,33 @@
+package talib
+
+import (
+	"math"
+	"testing"
+)
+
+// HtDcPhase once wrote the phase of bar 63 at 0 and so on, leaving its
+// outputs 63 bars ahead of the bars they belong to
+func TestHtDcPhase(t *testing.T) {
+	closes := testQuotes().Closes
+	phase := HtDcPhase(closes)
+	sine, _ := HtSine(closes)
+	for i := range phase {
+		if i < 63 {
+			if phase[i] != 0 {
+				t.Fatalf("phase %v at %d, within the lookback", phase[i], i)
+			}
+			continue
+		}
+		// the sine is that of the phase of the same bar
+		if want := math.Sin(phase[i] * math.Pi / 180); math.Abs(sine[i]-want) > 1e-9 {
+			t.Fatalf("bar %d: sine %v of a phase of %v", i, sine[i], phase[i])
+		}
+	}
+	// no bar sees a later one
+	head := HtDcPhase(closes[:100])
+	for i := range head {
+		if head[i] != phase[i] {
+			t.Fatalf("bar %d: %v on 100 bars, %v on %d", i, head[i], phase[i], len(closes))
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package talib
+
+import "math"
+
+// WarmUpNaN sets the first lookback values of the outputs to NaN. The
+// functions leave them at 0, which a caller cannot tell from a value; the
+// lookback of each function is given by its Lookback function, such as
+// BBandsLookback for BBands.
+func WarmUpNaN(lookback int, outputs ...[]float64) {
+	for _, out := range outputs {
+		for i := 0; i < lookback && i < len(out); i++ {
+			out[i] = math.NaN()
+		}
+	}
+}
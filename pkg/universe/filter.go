@@ -0,0 +1,200 @@
+package universe
+
+import (
+	"fmt"
+	"pkg/quotes"
+	"pkg/symbols"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filter decides from its recent sessions whether a symbol can be traded
+// on a day: whether it is priced, traded and held enough for a position to
+// be filled. A limit of 0 is not checked.
+type Filter struct {
+	MinPrice          float64         // close of the day
+	MinTurnover       float64         // average daily traded value
+	MinDeliveryPct    float64         // average delivery %, not checked on quotes without delivery data
+	MaxZeroVolumeDays int             // sessions without volume allowed in the window
+	MinListingDays    int             // calendar days since listing
+	MinRSRank         float64         // relative strength rank against the benchmark, 1 to 99
+	Window            int             // sessions averaged, 20 when 0
+	Master            *symbols.Master // listing dates; the first session is taken as the listing when unknown
+	Ranks             *quotes.RSRanks // the ranks MinRSRank checks; a symbol without one fails
+}
+
+// Reason is why a filter excludes a symbol
+type Reason int
+
+const (
+	NoSession   Reason = iota + 1 // no session on the day
+	LowPrice                      // close below MinPrice
+	LowTurnover                   // average traded value below MinTurnover
+	LowDelivery                   // average delivery % below MinDeliveryPct
+	ZeroVolume                    // more sessions without volume than MaxZeroVolumeDays
+	NewListing                    // listed less than MinListingDays ago
+	WeakRS                        // relative strength rank below MinRSRank
+)
+
+func (r Reason) String() string {
+	switch r {
+	case NoSession:
+		return "no session"
+	case LowPrice:
+		return "price"
+	case LowTurnover:
+		return "traded value"
+	case LowDelivery:
+		return "delivery %"
+	case ZeroVolume:
+		return "sessions without volume"
+	case NewListing:
+		return "days listed"
+	case WeakRS:
+		return "rs rank"
+	}
+	return "unknown"
+}
+
+// Exclusion is a check a symbol failed, with its value and the limit
+type Exclusion struct {
+	Reason Reason
+	Value  float64
+	Limit  float64
+}
+
+func (e Exclusion) String() string {
+	switch e.Reason {
+	case NoSession:
+		return e.Reason.String()
+	case ZeroVolume:
+		return fmt.Sprintf("%s %.0f above %.0f", e.Reason, e.Value, e.Limit)
+	case NewListing, WeakRS:
+		return fmt.Sprintf("%s %.0f below %.0f", e.Reason, e.Value, e.Limit)
+	}
+	return fmt.Sprintf("%s %.2f below %.2f", e.Reason, e.Value, e.Limit)
+}
+
+// Exclusions returns the checks session i of the quotes fails, none when
+// the symbol can be traded that day
+func (f *Filter) Exclusions(q *quotes.QuoteData, i int) []Exclusion {
+	if i < 0 || i >= len(q.Dates) {
+		return []Exclusion{{Reason: NoSession}}
+	}
+	var failed []Exclusion
+	check := func(r Reason, value float64, limit float64, ok bool) {
+		if !ok {
+			failed = append(failed, Exclusion{Reason: r, Value: value, Limit: limit})
+		}
+	}
+	if f.MinPrice > 0 {
+		check(LowPrice, q.Closes[i], f.MinPrice, q.Closes[i] >= f.MinPrice)
+	}
+	from := i - f.window() + 1
+	if from < 0 {
+		from = 0
+	}
+	n := float64(i - from + 1)
+	if f.MinTurnover > 0 {
+		turnover := 0.0
+		for k := from; k <= i; k++ {
+			if q.Has(quotes.Turnover) && q.Turnovers[k] > 0 {
+				turnover += q.Turnovers[k]
+			} else {
+				turnover += q.Closes[k] * q.Volumes[k]
+			}
+		}
+		turnover /= n
+		check(LowTurnover, turnover, f.MinTurnover, turnover >= f.MinTurnover)
+	}
+	if f.MinDeliveryPct > 0 && q.Has(quotes.DeliveryPct) {
+		delivery := 0.0
+		for k := from; k <= i; k++ {
+			delivery += q.DeliveryPcts[k]
+		}
+		delivery /= n
+		check(LowDelivery, delivery, f.MinDeliveryPct, delivery >= f.MinDeliveryPct)
+	}
+	if f.MaxZeroVolumeDays > 0 {
+		zero := 0
+		for k := from; k <= i; k++ {
+			if q.Volumes[k] <= 0 {
+				zero++
+			}
+		}
+		check(ZeroVolume, float64(zero), float64(f.MaxZeroVolumeDays), zero <= f.MaxZeroVolumeDays)
+	}
+	if f.MinListingDays > 0 {
+		days := q.Dates[i].Sub(f.listed(q)).Hours() / 24
+		check(NewListing, days, float64(f.MinListingDays), days >= float64(f.MinListingDays))
+	}
+	if f.MinRSRank > 0 {
+		rank := 0.0
+		if f.Ranks != nil {
+			rank, _ = f.Ranks.Rank(q.Symbol, q.Dates[i])
+		}
+		check(WeakRS, rank, f.MinRSRank, rank >= f.MinRSRank)
+	}
+	return failed
+}
+
+// Tradable returns true when session i of the quotes passes every check
+func (f *Filter) Tradable(q *quotes.QuoteData, i int) bool {
+	return len(f.Exclusions(q, i)) == 0
+}
+
+// Screen sorts the symbols into those tradable on the date and those not,
+// with the reasons each was excluded. The session judged is the last one of
+// any of the symbols on or before the date, so a screen on a holiday judges
+// the session before it. A symbol without that session, such as one
+// suspended or delisted since, or delisted by the master on the date, has
+// no session.
+func (f *Filter) Screen(data []*quotes.QuoteData, date time.Time) ([]string, map[string][]Exclusion) {
+	var session time.Time
+	for _, q := range data {
+		if i := q.Index(date, quotes.Previous); i >= 0 && q.Dates[i].After(session) {
+			session = q.Dates[i]
+		}
+	}
+	var tradable []string
+	excluded := make(map[string][]Exclusion)
+	for _, q := range data {
+		i := -1
+		if !session.IsZero() && (f.Master == nil || f.Master.Resolve(q.Symbol).ListedOn(date)) {
+			i = q.Index(session, quotes.Exact)
+		}
+		if failed := f.Exclusions(q, i); len(failed) > 0 {
+			excluded[q.Symbol] = failed
+		} else {
+			tradable = append(tradable, q.Symbol)
+		}
+	}
+	sort.Strings(tradable)
+	return tradable, excluded
+}
+
+// Explain joins the reasons of an exclusion for a report
+func Explain(failed []Exclusion) string {
+	reasons := make([]string, len(failed))
+	for k, e := range failed {
+		reasons[k] = e.String()
+	}
+	return strings.Join(reasons, ", ")
+}
+
+func (f *Filter) window() int {
+	if f.Window <= 0 {
+		return 20
+	}
+	return f.Window
+}
+
+func (f *Filter) listed(q *quotes.QuoteData) time.Time {
+	if f.Master != nil {
+		if s := f.Master.Resolve(q.Symbol); !s.Listed.IsZero() {
+			return s.Listed
+		}
+	}
+	return q.Dates[0]
+}
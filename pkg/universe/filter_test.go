@@ -0,0 +1,168 @@
+package universe
+
+import (
+	"pkg/quotes"
+	"pkg/symbols"
+	"testing"
+	"time"
+)
+
+// sessions returns n daily sessions of the symbol at a constant close and volume
+func sessions(symbol string, n int, close float64, volume float64) *quotes.QuoteData {
+	q := &quotes.QuoteData{Symbol: symbol}
+	for i := 0; i < n; i++ {
+		q.Dates = append(q.Dates, day(2018, 1, 1+i))
+		q.Opens = append(q.Opens, close)
+		q.Highs = append(q.Highs, close)
+		q.Lows = append(q.Lows, close)
+		q.Closes = append(q.Closes, close)
+		q.AdjCloses = append(q.AdjCloses, close)
+		q.Volumes = append(q.Volumes, volume)
+	}
+	return q
+}
+
+// fill returns a series of n values, the last of them set to last
+func fill(n int, value float64, last ...float64) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = value
+	}
+	copy(s[n-len(last):], last)
+	return s
+}
+
+func TestExclusions(t *testing.T) {
+	// 30 sessions at 10 x 100 shares, 1000 traded a day
+	plain := sessions("TEST", 30, 10, 100)
+	burst := sessions("TEST", 30, 10, 100)
+	burst.Volumes = fill(30, 100, 1000)
+	turnover := sessions("TEST", 30, 10, 100)
+	turnover.Turnovers = fill(30, 5000, 0, 0)
+	delivery := sessions("TEST", 30, 10, 100)
+	delivery.DeliveryPcts = fill(30, 30, 60, 60, 60, 60, 60)
+	halted := sessions("TEST", 30, 10, 100)
+	halted.Volumes = fill(30, 100, 0, 100, 100, 0, 0, 100, 100, 100, 100, 100)
+	listed := symbols.New("NSE")
+	listed.Add(&symbols.Symbol{Symbol: "TEST", Listed: day(2017, 1, 1)})
+	late := symbols.New("NSE")
+	late.Add(&symbols.Symbol{Symbol: "TEST", Listed: day(2018, 1, 21)})
+
+	for _, c := range []struct {
+		name   string
+		filter Filter
+		q      *quotes.QuoteData
+		i      int
+		reason Reason // 0 when tradable
+		value  float64
+	}{
+		{"price", Filter{MinPrice: 20}, plain, 29, LowPrice, 10},
+		{"price met", Filter{MinPrice: 10}, plain, 29, 0, 0},
+		{"turnover of the window", Filter{MinTurnover: 3000, Window: 5}, burst, 29, LowTurnover, 2800},
+		{"turnover of 20 sessions by default", Filter{MinTurnover: 3000}, burst, 29, LowTurnover, 1450},
+		{"turnover of a short history", Filter{MinTurnover: 3000, Window: 5}, burst, 1, LowTurnover, 1000},
+		{"turnover column", Filter{MinTurnover: 5000, Window: 2}, turnover, 27, 0, 0},
+		{"turnover of close x volume without a value", Filter{MinTurnover: 4000, Window: 4}, turnover, 29, LowTurnover, 3000},
+		{"turnover of close x volume without the column", Filter{MinTurnover: 1000}, plain, 29, 0, 0},
+		{"delivery of the window", Filter{MinDeliveryPct: 40, Window: 5}, delivery, 29, 0, 0},
+		{"delivery of 20 sessions by default", Filter{MinDeliveryPct: 40}, delivery, 29, LowDelivery, 37.5},
+		{"delivery without data", Filter{MinDeliveryPct: 40}, plain, 29, 0, 0},
+		{"sessions without volume", Filter{MaxZeroVolumeDays: 2, Window: 10}, halted, 29, ZeroVolume, 3},
+		{"sessions without volume in the window", Filter{MaxZeroVolumeDays: 2, Window: 5}, halted, 29, 0, 0},
+		{"listing from the first session", Filter{MinListingDays: 25}, plain, 20, NewListing, 20},
+		{"listing from the first session met", Filter{MinListingDays: 25}, plain, 29, 0, 0},
+		{"listing from the master", Filter{MinListingDays: 25, Master: listed}, plain, 0, 0, 0},
+		{"listing from the master after the first session", Filter{MinListingDays: 25, Master: late}, plain, 29, NewListing, 9},
+		{"no session", Filter{}, plain, 30, NoSession, 0},
+	} {
+		failed := c.filter.Exclusions(c.q, c.i)
+		if c.reason == 0 {
+			if len(failed) > 0 {
+				t.Errorf("%s: excluded for %s", c.name, Explain(failed))
+			}
+			continue
+		}
+		if len(failed) != 1 || failed[0].Reason != c.reason || failed[0].Value != c.value {
+			t.Errorf("%s: excluded for %v, want %s %v", c.name, failed, c.reason, c.value)
+		}
+	}
+}
+
+func TestScreen(t *testing.T) {
+	liquid := sessions("LIQUID", 30, 100, 1000)
+	thin := sessions("THIN", 30, 100, 10)
+	penny := sessions("PENNY", 30, 5, 100000)
+	stale := sessions("STALE", 10, 100, 1000) // last traded on 10-01-2018
+	later := sessions("LATER", 30, 100, 1000)
+	for i := range later.Dates {
+		later.Dates[i] = later.Dates[i].AddDate(0, 1, 0)
+	}
+	delisted := sessions("DELISTED", 30, 100, 1000)
+	master := symbols.New("NSE")
+	master.Add(&symbols.Symbol{Symbol: "DELISTED", Delisted: day(2018, 1, 20)})
+	f := &Filter{MinPrice: 20, MinTurnover: 50000, Window: 5, Master: master}
+	data := []*quotes.QuoteData{thin, penny, liquid, stale, later, delisted}
+	// the 30th is the last session, the 31st a holiday
+	for _, date := range []time.Time{day(2018, 1, 30), day(2018, 1, 31)} {
+		tradable, excluded := f.Screen(data, date)
+		if len(tradable) != 1 || tradable[0] != "LIQUID" {
+			t.Errorf("tradable on %v: %v", date, tradable)
+		}
+		for symbol, want := range map[string]string{
+			"THIN":     "traded value 1000.00 below 50000.00",
+			"PENNY":    "price 5.00 below 20.00",
+			"STALE":    "no session",
+			"LATER":    "no session",
+			"DELISTED": "no session",
+		} {
+			if got := Explain(excluded[symbol]); got != want {
+				t.Errorf("%s excluded on %v for %q, want %q", symbol, date, got, want)
+			}
+		}
+	}
+	// STALE on its own last session
+	if tradable, _ := f.Screen(data, day(2018, 1, 10)); len(tradable) != 3 || tradable[1] != "LIQUID" || tradable[2] != "STALE" {
+		t.Errorf("tradable %v", tradable)
+	}
+}
+
+func TestRSRankFilter(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	closes := func(daily float64) []float64 {
+		c := make([]float64, 300)
+		for i := range c {
+			c[i] = 100 * (1 + daily*float64(i))
+		}
+		return c
+	}
+	g := &quotes.Generator{Start: start, Seed: 1}
+	g.Symbol = "INDEX"
+	bench := g.FromCloses(closes(0.001))
+	var rels []*quotes.Relative
+	var data []*quotes.QuoteData
+	for _, s := range []struct {
+		symbol string
+		daily  float64
+	}{{"WEAK", 0}, {"FLAT", 0.001}, {"STRONG", 0.003}} {
+		g.Symbol = s.symbol
+		qd := g.FromCloses(closes(s.daily))
+		data = append(data, qd)
+		rels = append(rels, quotes.NewRelative(qd, bench, quotes.DailyRSPeriods))
+	}
+	f := &Filter{MinRSRank: 50, Ranks: quotes.NewRSRanks(rels)}
+	last := bench.Dates[len(bench.Dates)-1]
+	tradable, excluded := f.Screen(data, last)
+	if len(tradable) != 2 || tradable[0] != "FLAT" || tradable[1] != "STRONG" {
+		t.Errorf("tradable %v, excluded %v", tradable, excluded)
+	}
+	if failed := excluded["WEAK"]; len(failed) != 1 || failed[0].Reason != WeakRS || failed[0].Value != 1 {
+		t.Errorf("WEAK excluded for %v", failed)
+	}
+	// no rank before the Mansfield window fills
+	if failed := f.Exclusions(data[2], 10); len(failed) != 1 || failed[0].Reason != WeakRS {
+		t.Errorf("early session excluded for %v", failed)
+	}
+	if failed := (&Filter{MinRSRank: 50}).Exclusions(data[2], 299); len(failed) != 1 {
+		t.Errorf("without ranks excluded for %v", failed)
+	}
+}
@@ -0,0 +1,215 @@
+// Package universe defines the symbols a strategy may trade and the dates
+// each of them could be traded, so that backtests are not run on the
+// survivors of today alone
+package universe
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"pkg/calendar"
+	"pkg/symbols"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Period is a range of days, both ends included. A zero end leaves that
+// side open.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// Contains returns true when the calendar day of the date, in its own time
+// zone, falls within the period
+func (p Period) Contains(date time.Time) bool {
+	y, m, d := date.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if !p.From.IsZero() && day.Before(p.From) {
+		return false
+	}
+	return p.To.IsZero() || !day.After(p.To)
+}
+
+func (p Period) String() string {
+	format := func(t time.Time) string {
+		if t.IsZero() {
+			return "open"
+		}
+		return t.Format("2006-01-02")
+	}
+	return format(p.From) + " to " + format(p.To)
+}
+
+// Universe is a named set of symbols with the periods each was a member,
+// such as the membership history of an index or a watchlist
+type Universe struct {
+	Name     string
+	Window   Period          // the dates a backtest of the universe covers
+	Master   *symbols.Master // resolves names; symbols are not members outside their listing when set
+	included map[string][]Period
+	excluded map[string][]Period
+}
+
+// New returns an empty universe
+func New(name string, master *symbols.Master) *Universe {
+	return &Universe{
+		Name:     name,
+		Master:   master,
+		included: make(map[string][]Period),
+		excluded: make(map[string][]Period),
+	}
+}
+
+// Include makes the symbol a member over the period
+func (u *Universe) Include(symbol string, p Period) {
+	symbol = u.ticker(symbol)
+	u.included[symbol] = append(u.included[symbol], p)
+}
+
+// Exclude keeps the symbol out over the period, even where it is included
+func (u *Universe) Exclude(symbol string, p Period) {
+	symbol = u.ticker(symbol)
+	u.excluded[symbol] = append(u.excluded[symbol], p)
+}
+
+// Member returns true when the symbol was in the universe on the date
+func (u *Universe) Member(symbol string, date time.Time) bool {
+	if !u.Window.Contains(date) {
+		return false
+	}
+	symbol = u.ticker(symbol)
+	if u.Master != nil && !u.Master.Resolve(symbol).ListedOn(date) {
+		return false
+	}
+	for _, p := range u.excluded[symbol] {
+		if p.Contains(date) {
+			return false
+		}
+	}
+	for _, p := range u.included[symbol] {
+		if p.Contains(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// Symbols returns every symbol that was ever included, in ticker order
+func (u *Universe) Symbols() []string {
+	list := make([]string, 0, len(u.included))
+	for symbol := range u.included {
+		list = append(list, symbol)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// On returns the members on the date, in ticker order
+func (u *Universe) On(date time.Time) []string {
+	var list []string
+	for _, symbol := range u.Symbols() {
+		if u.Member(symbol, date) {
+			list = append(list, symbol)
+		}
+	}
+	return list
+}
+
+// Periods returns the periods the symbol was included
+func (u *Universe) Periods(symbol string) []Period {
+	return u.included[u.ticker(symbol)]
+}
+
+func (u *Universe) ticker(symbol string) string {
+	if u.Master == nil {
+		return strings.ToUpper(strings.TrimSpace(symbol))
+	}
+	return u.Master.Ticker(symbol)
+}
+
+// File returns the file of a named universe in a folder of universes
+func File(dir string, name string) string {
+	return filepath.Join(dir, name+".csv")
+}
+
+// Load reads a universe named after its file. Each line is a rule, a
+// symbol and a period; dates are dd-mm-yyyy or yyyy-mm-dd, both ends are
+// included and an empty date leaves that side open. Lines starting with #
+// are ignored:
+//
+//	# Rule,Symbol,From,To
+//	window,,01-01-2012,31-12-2020
+//	include,HDFCBANK,,
+//	include,BAJFINANCE,29-09-2017,
+//	exclude,BAJFINANCE,01-03-2020,30-04-2020
+//
+// window sets the dates a backtest covers, include adds a membership
+// period and exclude keeps a symbol out whatever its memberships.
+func Load(file string, master *symbols.Master) (*Universe, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	u := New(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), master)
+	reader := csv.NewReader(bufio.NewReader(f))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	for {
+		line, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		if err := u.parseRule(line); err != nil {
+			lineno, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("%s: line %d: %v", file, lineno, err)
+		}
+	}
+	return u, nil
+}
+
+func (u *Universe) parseRule(line []string) error {
+	field := func(i int) string {
+		if i < len(line) {
+			return strings.TrimSpace(line[i])
+		}
+		return ""
+	}
+	var p Period
+	var err error
+	if v := field(2); v != "" {
+		if p.From, err = calendar.ParseDate(v); err != nil {
+			return err
+		}
+	}
+	if v := field(3); v != "" {
+		if p.To, err = calendar.ParseDate(v); err != nil {
+			return err
+		}
+	}
+	if !p.From.IsZero() && !p.To.IsZero() && p.To.Before(p.From) {
+		return fmt.Errorf("period %s ends before it starts", p)
+	}
+	rule, symbol := strings.ToLower(field(0)), field(1)
+	if rule != "window" && symbol == "" {
+		return fmt.Errorf("%s without a symbol", rule)
+	}
+	switch rule {
+	case "window":
+		u.Window = p
+	case "include":
+		u.Include(symbol, p)
+	case "exclude":
+		u.Exclude(symbol, p)
+	default:
+		return fmt.Errorf("unknown rule %q", field(0))
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package universe
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"pkg/symbols"
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPeriodContains(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+30*60)
+	spring := Period{From: day(2020, 3, 1), To: day(2020, 4, 30)}
+	for _, c := range []struct {
+		p    Period
+		date time.Time
+		want bool
+	}{
+		{spring, day(2020, 2, 29), false},
+		{spring, day(2020, 3, 1), true},
+		{spring, time.Date(2020, 3, 1, 3, 0, 0, 0, ist), true}, // still 29-02 in UTC
+		{spring, time.Date(2020, 4, 30, 23, 59, 0, 0, time.UTC), true},
+		{spring, day(2020, 5, 1), false},
+		{spring, time.Date(2020, 5, 1, 3, 0, 0, 0, ist), false}, // still 30-04 in UTC
+		{Period{To: day(2020, 4, 30)}, day(1990, 1, 1), true},
+		{Period{From: day(2020, 3, 1)}, day(2090, 1, 1), true},
+		{Period{}, day(2020, 1, 1), true},
+	} {
+		if got := c.p.Contains(c.date); got != c.want {
+			t.Errorf("%s contains %v: %v", c.p, c.date, got)
+		}
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	for _, c := range []struct {
+		line string
+		ok   bool
+	}{
+		{"window,,01-01-2012,31-12-2020", true},
+		{"include,HDFCBANK,,", true},
+		{"include,BAJFINANCE,2017-09-29", true},
+		{"exclude,BAJFINANCE,01-03-2020,01-03-2020", true},
+		{"Include, DABUR ,,", true},
+		{"include,,01-01-2012,", false},
+		{"exclude,BAJFINANCE,01-03-2020,29-02-2020", false},
+		{"include,HDFCBANK,31-02-2020,", false},
+		{"include,HDFCBANK,2020/01/01,", false},
+		{"remove,HDFCBANK,,", false},
+	} {
+		u := New("TEST", nil)
+		err := u.parseRule(strings.Split(c.line, ","))
+		if (err == nil) != c.ok {
+			t.Errorf("%q: %v", c.line, err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "universe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	data := "# Rule,Symbol,From,To\n" +
+		"window,,01-01-2012,31-12-2020\n" +
+		"include,HDFCBANK,,\n" +
+		"include,BAJFINANCE.NS,29-09-2017,\n" +
+		"exclude,BAJFINANCE,01-03-2020,30-04-2020\n"
+	file := File(dir, "NIFTY")
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	u, err := Load(file, symbols.New("NSE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "NIFTY" || !u.Window.From.Equal(day(2012, 1, 1)) || !u.Window.To.Equal(day(2020, 12, 31)) {
+		t.Errorf("universe %s over %s", u.Name, u.Window)
+	}
+	if s := u.Symbols(); len(s) != 2 || s[0] != "BAJFINANCE" || s[1] != "HDFCBANK" {
+		t.Errorf("symbols %v", s)
+	}
+	for _, c := range []struct {
+		date time.Time
+		want string
+	}{
+		{day(2011, 12, 30), ""},
+		{day(2012, 1, 2), "HDFCBANK"},
+		{day(2017, 9, 28), "HDFCBANK"},
+		{day(2017, 9, 29), "BAJFINANCE HDFCBANK"},
+		{day(2020, 3, 2), "HDFCBANK"},
+		{day(2020, 4, 30), "HDFCBANK"},
+		{day(2020, 5, 1), "BAJFINANCE HDFCBANK"},
+		{day(2020, 12, 31), "BAJFINANCE HDFCBANK"},
+		{day(2021, 1, 1), ""},
+	} {
+		if got := strings.Join(u.On(c.date), " "); got != c.want {
+			t.Errorf("members on %v: %q, want %q", c.date.Format("2006-01-02"), got, c.want)
+		}
+	}
+
+	bad := filepath.Join(dir, "BAD.csv")
+	if err := ioutil.WriteFile(bad, []byte(data+"include,,,\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(bad, nil); err == nil || !strings.Contains(err.Error(), "line 6") {
+		t.Errorf("bad rule: %v", err)
+	}
+}